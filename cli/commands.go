@@ -7,6 +7,7 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"encoding/json"
 	"flag"
 	"fmt"
 	"golang.org/x/term"
@@ -16,6 +17,7 @@ import (
 	"os/exec"
 	"reflect"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"text/tabwriter"
@@ -95,16 +97,19 @@ func (c *cli) getMethod(name string) (reflect.Method, bool) {
 
 func (c *cli) CmdHelp() error {
 	help := fmt.Sprintf("Usage: mirrorbits [OPTIONS] COMMAND [arg...]\n\nA smart download redirector.\n\n")
-	help += fmt.Sprintf("Server commands:\n    %-10.10s%s\n\n", "daemon", "Start the server")
+	help += fmt.Sprintf("Server commands:\n    %-12.12s%s\n\n", "daemon", "Start the server")
 	help += fmt.Sprintf("CLI commands:\n")
 	for _, command := range [][]string{
 		{"add", "Add a new mirror"},
 		{"disable", "Disable a mirror"},
 		{"edit", "Edit a mirror"},
 		{"enable", "Enable a mirror"},
+		{"explain", "Explain the selection of the mirrors"},
 		{"export", "Export the mirror database"},
 		{"list", "List all mirrors"},
 		{"logs", "Print logs of a mirror"},
+		{"maintenance", "Schedule maintenance windows"},
+		{"networks", "Route client networks to a mirror"},
 		{"refresh", "Refresh the local repository"},
 		{"reload", "Reload configuration"},
 		{"remove", "Remove a mirror"},
@@ -114,7 +119,7 @@ func (c *cli) CmdHelp() error {
 		{"upgrade", "Seamless binary upgrade"},
 		{"version", "Print version information"},
 	} {
-		help += fmt.Sprintf("    %-10.10s%s\n", command[0], command[1])
+		help += fmt.Sprintf("    %-12.12s%s\n", command[0], command[1])
 	}
 	fmt.Fprintf(os.Stderr, "%s\n", help)
 	return nil
@@ -235,6 +240,8 @@ func (c *cli) CmdList(args ...string) error {
 		if *state == true {
 			if mirror.Enabled == false {
 				fmt.Fprintf(w, "\tdisabled")
+			} else if mirror.InMaintenance == true {
+				fmt.Fprintf(w, "\tmaintenance")
 			} else if mirror.Up == true {
 				fmt.Fprintf(w, "\tup")
 			} else {
@@ -264,12 +271,14 @@ func (c *cli) CmdAdd(args ...string) error {
 	continentOnly := cmd.Bool("continent-only", false, "The mirror should only handle its continent")
 	countryOnly := cmd.Bool("country-only", false, "The mirror should only handle its country")
 	asOnly := cmd.Bool("as-only", false, "The mirror should only handle clients in the same AS number")
+	private := cmd.Bool("private", false, "The mirror should only handle clients matching its network rules")
 	score := cmd.Int("score", 0, "Weight to give to the mirror during selection")
 	comment := cmd.String("comment", "", "Comment")
 	netBandwidth := cmd.Int64("net-bandwidth", 1000, "The downstream network bandwidth defaults to 1000mb/s,Unit: mb/s")
 	latitude := cmd.Float64("latitude", 0, "latitude (-90~90)")
 	longitude := cmd.Float64("longitude", 0, "longitude (-180~180)")
 	country := cmd.String("country", "", "Name of country")
+	redirectDomains := cmd.String("redirect-domains", "", "Space or comma separated list of domains the mirror is allowed to redirect to")
 
 	if err := cmd.Parse(args); err != nil {
 		log.Fatal("err: ", err)
@@ -301,25 +310,27 @@ func (c *cli) CmdAdd(args ...string) error {
 	}
 
 	mirror := &mirrors.Mirror{
-		Name:             cmd.Arg(0),
-		HttpURL:          *http,
-		RsyncURL:         *rsync,
-		FtpURL:           *ftp,
-		SponsorName:      *sponsorName,
-		SponsorURL:       *sponsorURL,
-		SponsorLogoURL:   *sponsorLogo,
-		AdminName:        *adminName,
-		AdminEmail:       *adminEmail,
-		CustomData:       *customData,
-		ContinentOnly:    *continentOnly,
-		CountryOnly:      *countryOnly,
-		ASOnly:           *asOnly,
-		Score:            *score,
-		Comment:          *comment,
-		NetworkBandwidth: int32(*netBandwidth),
-		Latitude:         float32(*latitude),
-		Longitude:        float32(*longitude),
-		Country:          *country,
+		Name:                   cmd.Arg(0),
+		HttpURL:                *http,
+		RsyncURL:               *rsync,
+		FtpURL:                 *ftp,
+		SponsorName:            *sponsorName,
+		SponsorURL:             *sponsorURL,
+		SponsorLogoURL:         *sponsorLogo,
+		AdminName:              *adminName,
+		AdminEmail:             *adminEmail,
+		CustomData:             *customData,
+		ContinentOnly:          *continentOnly,
+		CountryOnly:            *countryOnly,
+		ASOnly:                 *asOnly,
+		Private:                *private,
+		Score:                  *score,
+		Comment:                *comment,
+		NetworkBandwidth:       int32(*netBandwidth),
+		Latitude:               float32(*latitude),
+		Longitude:              float32(*longitude),
+		Country:                *country,
+		AllowedRedirectDomains: *redirectDomains,
 	}
 
 	client := c.GetRPC()
@@ -351,6 +362,9 @@ func (c *cli) CmdAdd(args ...string) error {
 		fmt.Printf("Longitude: %.4f\n", reply.Longitude)
 		fmt.Printf("Continent: %s\n", reply.Continent)
 		fmt.Printf("Country:   %s\n", reply.Country)
+		if reply.Subdivision != "" {
+			fmt.Printf("Subdivision: %s\n", reply.Subdivision)
+		}
 		fmt.Printf("NetworkBandwidth:   %d\n", reply.NetworkBandwidth)
 		fmt.Printf("ASN:       %s\n", reply.ASN)
 		fmt.Println("")
@@ -575,13 +589,18 @@ func CompareAndUpdate(mirror *mirrors.Mirror, updateMirror *mirrors.Mirror) bool
 		mirror.ContinentOnly == updateMirror.ContinentOnly &&
 		mirror.CountryOnly == updateMirror.CountryOnly &&
 		mirror.ASOnly == updateMirror.ASOnly &&
+		mirror.Private == updateMirror.Private &&
 		mirror.Score == updateMirror.Score &&
 		mirror.Enabled == updateMirror.Enabled &&
 		mirror.SponsorLogoURL == updateMirror.SponsorLogoURL &&
 		mirror.NetworkBandwidth == updateMirror.NetworkBandwidth &&
 		mirror.Latitude == updateMirror.Latitude &&
 		mirror.Longitude == updateMirror.Longitude &&
-		mirror.Country == updateMirror.Country {
+		mirror.Country == updateMirror.Country &&
+		mirror.SubdivisionCode == updateMirror.SubdivisionCode &&
+		mirror.AllowRedirects == updateMirror.AllowRedirects &&
+		mirror.AllowedRedirectDomains == updateMirror.AllowedRedirectDomains &&
+		reflect.DeepEqual(mirror.ShareCaps, updateMirror.ShareCaps) {
 		return false
 	}
 	mirror.HttpURL = updateMirror.HttpURL
@@ -594,6 +613,7 @@ func CompareAndUpdate(mirror *mirrors.Mirror, updateMirror *mirrors.Mirror) bool
 	mirror.ContinentOnly = updateMirror.ContinentOnly
 	mirror.CountryOnly = updateMirror.CountryOnly
 	mirror.ASOnly = updateMirror.ASOnly
+	mirror.Private = updateMirror.Private
 	mirror.Score = updateMirror.Score
 	mirror.Enabled = updateMirror.Enabled
 	mirror.SponsorLogoURL = updateMirror.SponsorLogoURL
@@ -601,6 +621,10 @@ func CompareAndUpdate(mirror *mirrors.Mirror, updateMirror *mirrors.Mirror) bool
 	mirror.Latitude = updateMirror.Latitude
 	mirror.Longitude = updateMirror.Longitude
 	mirror.Country = updateMirror.Country
+	mirror.SubdivisionCode = updateMirror.SubdivisionCode
+	mirror.AllowRedirects = updateMirror.AllowRedirects
+	mirror.AllowedRedirectDomains = updateMirror.AllowedRedirectDomains
+	mirror.ShareCaps = updateMirror.ShareCaps
 	return true
 }
 
@@ -826,7 +850,14 @@ func (c *cli) CmdShow(args ...string) error {
 		log.Fatal("show error:", err)
 	}
 
-	fmt.Printf("%s\nComment:\n%s\n", out, mirror.Comment)
+	fmt.Printf("%s", out)
+	if mirror.ResolvedURL != "" && mirror.ResolvedURL != mirror.HttpURL {
+		fmt.Printf("ResolvedURL: %s\n", mirror.ResolvedURL)
+		if mirror.IsRedirectedToOtherHost() {
+			fmt.Printf("Warning: the mirror redirects to a different host\n")
+		}
+	}
+	fmt.Printf("\nComment:\n%s\n", mirror.Comment)
 	return nil
 }
 
@@ -1090,6 +1121,394 @@ func (c *cli) CmdLogs(args ...string) error {
 	return nil
 }
 
+func (c *cli) CmdExplain(args ...string) error {
+	cmd := SubCmd("explain", "[OPTIONS] IP PATH", "Explain the selection of the mirrors serving a file to a client")
+	jsonOutput := cmd.Bool("json", false, "Print the explanation as JSON")
+
+	if err := cmd.Parse(args); err != nil {
+		return nil
+	}
+	if cmd.NArg() != 2 {
+		cmd.Usage()
+		return nil
+	}
+
+	path := cmd.Arg(1)
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+
+	client := c.GetRPC()
+	ctx, cancel := context.WithTimeout(context.Background(), defaultRPCTimeout)
+	defer cancel()
+	reply, err := client.Explain(ctx, &rpc.ExplainRequest{
+		IP:   cmd.Arg(0),
+		Path: path,
+	})
+	if err != nil {
+		return errors.New(status.Convert(err).Message())
+	}
+
+	if *jsonOutput {
+		output, err := json.MarshalIndent(reply, "", "    ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(output))
+		return nil
+	}
+
+	ci := reply.ClientInfo
+	fmt.Printf("Client:    %s\n", reply.IP)
+	if ci != nil && ci.CountryCode != "" {
+		fmt.Printf("Location:  %s, %s (%s, %s) %.4f %.4f\n", ci.City, ci.Country, ci.CountryCode, ci.ContinentCode, ci.Latitude, ci.Longitude)
+		if ci.SubdivisionCode != "" {
+			fmt.Printf("Region:    %s\n", ci.SubdivisionCode)
+		}
+		fmt.Printf("Network:   AS%d %s\n", ci.ASNum, ci.ASName)
+		if ci.ASNGroup != "" {
+			fmt.Printf("ASN group: %s\n", ci.ASNGroup)
+		}
+	} else {
+		fmt.Printf("Location:  unknown\n")
+	}
+	fmt.Printf("File:      %s (%d bytes)\n", reply.Path, reply.Size)
+	if reply.Fallback {
+		fmt.Printf("No mirror selected, the request would be served by a fallback\n")
+	}
+	fmt.Println()
+
+	w := new(tabwriter.Writer)
+	w.Init(os.Stdout, 0, 8, 0, '\t', 0)
+	fmt.Fprintf(w, "RANK\tNAME\tDISTANCE\tCOUNTRY\tSCORE\tDIST.SCORE\tWEIGHT\tNETWORK\tASN GROUP\tCAPPED\tFILE\tEXCLUDED\n")
+	for _, m := range reply.Mirrors {
+		rank := "-"
+		if m.Rank > 0 {
+			rank = strconv.Itoa(int(m.Rank))
+		}
+		weight := ""
+		if m.Weight > 0 {
+			weight = fmt.Sprintf("%.1f%%", m.Weight)
+		}
+		fmt.Fprintf(w, "%s \t%s \t%.0f km \t%d \t%d \t%d \t%s \t%s \t%s \t%s \t%s \t%s\n", rank, m.Name, m.Distance,
+			m.CountryScore, m.Score, m.DistanceScore, weight, m.NetworkMatch, m.ASNGroupMatch, m.ShareCapped, m.FileMatch, m.ExcludeReason)
+	}
+	w.Flush()
+	return nil
+}
+
+func (c *cli) CmdMaintenance(args ...string) error {
+	usage := func() {
+		fmt.Fprintf(os.Stderr, "\nUsage: mirrorbits maintenance COMMAND [OPTIONS] IDENTIFIER\n\nManage the maintenance windows of a mirror\n\n")
+		fmt.Fprintf(os.Stderr, "Commands:\n")
+		fmt.Fprintf(os.Stderr, "    %-10.10s%s\n", "add", "Schedule a new maintenance window")
+		fmt.Fprintf(os.Stderr, "    %-10.10s%s\n", "list", "List the maintenance windows")
+		fmt.Fprintf(os.Stderr, "    %-10.10s%s\n\n", "remove", "Remove a maintenance window")
+	}
+
+	if len(args) == 0 {
+		usage()
+		return nil
+	}
+
+	switch args[0] {
+	case "add":
+		return c.maintenanceAdd(args[1:]...)
+	case "list":
+		return c.maintenanceList(args[1:]...)
+	case "remove":
+		return c.maintenanceRemove(args[1:]...)
+	default:
+		usage()
+	}
+	return nil
+}
+
+func (c *cli) maintenanceAdd(args ...string) error {
+	cmd := SubCmd("maintenance add", "[OPTIONS] IDENTIFIER", "Schedule a new maintenance window")
+	start := cmd.String("start", "", "Start of the window (RFC3339 or 'YYYY-MM-DD HH:MM' local time, default now)")
+	end := cmd.String("end", "", "End of the window (RFC3339 or 'YYYY-MM-DD HH:MM' local time)")
+	duration := cmd.Duration("duration", 0, "Duration of the window (alternative to -end)")
+	reason := cmd.String("reason", "", "Reason of the maintenance")
+
+	if err := cmd.Parse(args); err != nil {
+		return nil
+	}
+	if cmd.NArg() != 1 || (*end == "") == (*duration == 0) {
+		cmd.Usage()
+		return nil
+	}
+
+	startTime := time.Now()
+	if *start != "" {
+		t, err := parseDate(*start)
+		if err != nil {
+			return err
+		}
+		startTime = t
+	}
+
+	endTime := startTime.Add(*duration)
+	if *end != "" {
+		t, err := parseDate(*end)
+		if err != nil {
+			return err
+		}
+		endTime = t
+	}
+
+	startProto, err := ptypes.TimestampProto(startTime)
+	if err != nil {
+		return err
+	}
+	endProto, err := ptypes.TimestampProto(endTime)
+	if err != nil {
+		return err
+	}
+
+	id, name := c.matchMirror(cmd.Arg(0))
+
+	client := c.GetRPC()
+	ctx, cancel := context.WithTimeout(context.Background(), defaultRPCTimeout)
+	defer cancel()
+	window, err := client.AddMaintenance(ctx, &rpc.AddMaintenanceRequest{
+		MirrorID: int32(id),
+		Window: &rpc.MaintenanceWindow{
+			Start:  startProto,
+			End:    endProto,
+			Reason: *reason,
+		},
+	})
+	if err != nil {
+		return errors.New(status.Convert(err).Message())
+	}
+
+	fmt.Printf("Maintenance window #%d scheduled for %s from %s to %s\n", window.ID, name,
+		startTime.Format(time.RFC1123), endTime.Format(time.RFC1123))
+	return nil
+}
+
+func (c *cli) maintenanceList(args ...string) error {
+	cmd := SubCmd("maintenance list", "IDENTIFIER", "List the maintenance windows of a mirror")
+
+	if err := cmd.Parse(args); err != nil {
+		return nil
+	}
+	if cmd.NArg() != 1 {
+		cmd.Usage()
+		return nil
+	}
+
+	id, name := c.matchMirror(cmd.Arg(0))
+
+	client := c.GetRPC()
+	ctx, cancel := context.WithTimeout(context.Background(), defaultRPCTimeout)
+	defer cancel()
+	reply, err := client.ListMaintenance(ctx, &rpc.MirrorIDRequest{
+		ID: int32(id),
+	})
+	if err != nil {
+		log.Fatal("maintenance error:", err)
+	}
+
+	if len(reply.Windows) == 0 {
+		fmt.Printf("No maintenance window for %s\n", name)
+		return nil
+	}
+
+	now := time.Now()
+	w := new(tabwriter.Writer)
+	w.Init(os.Stdout, 0, 8, 0, '\t', 0)
+	fmt.Fprintf(w, "ID\tSTART\tEND\tSTATE\tREASON\n")
+	for _, window := range reply.Windows {
+		start, _ := ptypes.Timestamp(window.Start)
+		end, _ := ptypes.Timestamp(window.End)
+		state := "scheduled"
+		if !end.After(now) {
+			state = "expired"
+		} else if !start.After(now) {
+			state = "active"
+		}
+		fmt.Fprintf(w, "%d \t%s \t%s \t%s \t%s\n", window.ID,
+			start.Local().Format(time.RFC1123), end.Local().Format(time.RFC1123), state, window.Reason)
+	}
+	w.Flush()
+	return nil
+}
+
+func (c *cli) maintenanceRemove(args ...string) error {
+	cmd := SubCmd("maintenance remove", "IDENTIFIER WINDOW_ID", "Remove a maintenance window of a mirror")
+
+	if err := cmd.Parse(args); err != nil {
+		return nil
+	}
+	if cmd.NArg() != 2 {
+		cmd.Usage()
+		return nil
+	}
+
+	windowID, err := strconv.Atoi(cmd.Arg(1))
+	if err != nil {
+		return fmt.Errorf("Invalid window ID: %s", cmd.Arg(1))
+	}
+
+	id, name := c.matchMirror(cmd.Arg(0))
+
+	client := c.GetRPC()
+	ctx, cancel := context.WithTimeout(context.Background(), defaultRPCTimeout)
+	defer cancel()
+	_, err = client.RemoveMaintenance(ctx, &rpc.RemoveMaintenanceRequest{
+		MirrorID: int32(id),
+		WindowID: int32(windowID),
+	})
+	if err != nil {
+		return errors.New(status.Convert(err).Message())
+	}
+
+	fmt.Printf("Maintenance window #%d of %s removed\n", windowID, name)
+	return nil
+}
+
+func (c *cli) CmdNetworks(args ...string) error {
+	usage := func() {
+		fmt.Fprintf(os.Stderr, "\nUsage: mirrorbits networks COMMAND [OPTIONS] IDENTIFIER\n\nManage the client networks routed to a mirror\n\n")
+		fmt.Fprintf(os.Stderr, "Commands:\n")
+		fmt.Fprintf(os.Stderr, "    %-10.10s%s\n", "add", "Route a network to the mirror")
+		fmt.Fprintf(os.Stderr, "    %-10.10s%s\n", "list", "List the networks routed to the mirror")
+		fmt.Fprintf(os.Stderr, "    %-10.10s%s\n\n", "remove", "Remove a network rule")
+	}
+
+	if len(args) == 0 {
+		usage()
+		return nil
+	}
+
+	switch args[0] {
+	case "add":
+		return c.networksAdd(args[1:]...)
+	case "list":
+		return c.networksList(args[1:]...)
+	case "remove":
+		return c.networksRemove(args[1:]...)
+	default:
+		usage()
+	}
+	return nil
+}
+
+func (c *cli) networksAdd(args ...string) error {
+	cmd := SubCmd("networks add", "[OPTIONS] IDENTIFIER NETWORK", "Route the clients of a network (CIDR notation) to a mirror")
+	exclusive := cmd.Bool("exclusive", false, "The clients of the network should only be redirected to the matching mirrors")
+
+	if err := cmd.Parse(args); err != nil {
+		return nil
+	}
+	if cmd.NArg() != 2 {
+		cmd.Usage()
+		return nil
+	}
+
+	id, name := c.matchMirror(cmd.Arg(0))
+
+	client := c.GetRPC()
+	ctx, cancel := context.WithTimeout(context.Background(), defaultRPCTimeout)
+	defer cancel()
+	rule, err := client.AddNetworkRule(ctx, &rpc.AddNetworkRuleRequest{
+		MirrorID: int32(id),
+		Rule: &rpc.NetworkRule{
+			Network:   cmd.Arg(1),
+			Exclusive: *exclusive,
+		},
+	})
+	if err != nil {
+		return errors.New(status.Convert(err).Message())
+	}
+
+	fmt.Printf("Clients from %s are now routed to %s\n", rule.Network, name)
+	return nil
+}
+
+func (c *cli) networksList(args ...string) error {
+	cmd := SubCmd("networks list", "IDENTIFIER", "List the networks routed to a mirror")
+
+	if err := cmd.Parse(args); err != nil {
+		return nil
+	}
+	if cmd.NArg() != 1 {
+		cmd.Usage()
+		return nil
+	}
+
+	id, name := c.matchMirror(cmd.Arg(0))
+
+	client := c.GetRPC()
+	ctx, cancel := context.WithTimeout(context.Background(), defaultRPCTimeout)
+	defer cancel()
+	reply, err := client.ListNetworkRules(ctx, &rpc.MirrorIDRequest{
+		ID: int32(id),
+	})
+	if err != nil {
+		log.Fatal("networks error:", err)
+	}
+
+	if len(reply.Rules) == 0 {
+		fmt.Printf("No network routed to %s\n", name)
+		return nil
+	}
+
+	w := new(tabwriter.Writer)
+	w.Init(os.Stdout, 0, 8, 0, '\t', 0)
+	fmt.Fprintf(w, "NETWORK\tMODE\n")
+	for _, rule := range reply.Rules {
+		mode := "prefer"
+		if rule.Exclusive {
+			mode = "exclusive"
+		}
+		fmt.Fprintf(w, "%s \t%s\n", rule.Network, mode)
+	}
+	w.Flush()
+	return nil
+}
+
+func (c *cli) networksRemove(args ...string) error {
+	cmd := SubCmd("networks remove", "IDENTIFIER NETWORK", "Remove a network rule of a mirror")
+
+	if err := cmd.Parse(args); err != nil {
+		return nil
+	}
+	if cmd.NArg() != 2 {
+		cmd.Usage()
+		return nil
+	}
+
+	id, name := c.matchMirror(cmd.Arg(0))
+
+	client := c.GetRPC()
+	ctx, cancel := context.WithTimeout(context.Background(), defaultRPCTimeout)
+	defer cancel()
+	_, err := client.RemoveNetworkRule(ctx, &rpc.RemoveNetworkRuleRequest{
+		MirrorID: int32(id),
+		Network:  cmd.Arg(1),
+	})
+	if err != nil {
+		return errors.New(status.Convert(err).Message())
+	}
+
+	fmt.Printf("Network %s no longer routed to %s\n", cmd.Arg(1), name)
+	return nil
+}
+
+func parseDate(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02 15:04", value, time.Local)
+	if err != nil {
+		return t, fmt.Errorf("Invalid date: %s", value)
+	}
+	return t, nil
+}
+
 func (c *cli) CmdReload(args ...string) error {
 	client := c.GetRPC()
 	ctx, cancel := context.WithTimeout(context.Background(), defaultRPCTimeout)
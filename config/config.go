@@ -6,8 +6,10 @@ package config
 import (
 	"fmt"
 	"io/ioutil"
+	"net/url"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 
 	"github.com/op/go-logging"
@@ -56,10 +58,35 @@ func defaultConfig() Configuration {
 		},
 		DisallowRedirects:       false,
 		WeightDistributionRange: 1.5,
+		SelectionMode:           "score",
+		SubdivisionWeight:       1,
+		BandwidthWindow:         300,
+		BandwidthThreshold:      0,
 		DisableOnMissingFile:    false,
 		RPCListenAddress:        "localhost:3390",
 		RPCPassword:             "",
 		SchemaStrictMatch:       true,
+		RegionAliases: []RegionAlias{
+			{Codes: []string{"TW", "TWN", "TPE"}, Code: "CN", Country: "China"},
+			{Codes: []string{"HK", "HKG", "HKSAR"}, Code: "CN", Country: "China"},
+			{Codes: []string{"MO", "MC", "OMA"}, Code: "CN", Country: "China"},
+		},
+		Unlocated: Unlocated{
+			Policy: "random",
+		},
+		Torrent: Torrent{
+			WebSeeds: 10,
+		},
+		LocalServe: LocalServe{
+			MaxSize: 64 << 10,
+		},
+		Notifications: Notifications{
+			MaxRetries:    5,
+			RetryInterval: 60,
+			SMTP: SMTPServer{
+				OutageThreshold: 60,
+			},
+		},
 	}
 }
 
@@ -83,11 +110,16 @@ type Configuration struct {
 	ScanInterval              int        `yaml:"ScanInterval"`
 	CheckInterval             int        `yaml:"CheckInterval"`
 	RepositoryScanInterval    int        `yaml:"RepositoryScanInterval"`
+	SourceScanMaxAge          int        `yaml:"SourceScanMaxAge"`
 	MaxLinkHeaders            int        `yaml:"MaxLinkHeaders"`
 	FixTimezoneOffsets        bool       `yaml:"FixTimezoneOffsets"`
 	Hashes                    hashing    `yaml:"Hashes"`
 	DisallowRedirects         bool       `yaml:"DisallowRedirects"`
 	WeightDistributionRange   float32    `yaml:"WeightDistributionRange"`
+	SelectionMode             string     `yaml:"SelectionMode"`
+	SubdivisionWeight         int        `yaml:"SubdivisionWeight"`
+	BandwidthWindow           int        `yaml:"BandwidthWindow"`
+	BandwidthThreshold        float32    `yaml:"BandwidthThreshold"`
 	DisableOnMissingFile      bool       `yaml:"DisableOnMissingFile"`
 	Fallbacks                 []Fallback `yaml:"Fallbacks"`
 	SchemaStrictMatch         bool       `yaml:"SchemaStrictMatch"`
@@ -101,6 +133,136 @@ type Configuration struct {
 	PreReleaseVersion   string           `yaml:"PreReleaseVersion"`
 	RepositoryFilter    DirFilter        `yaml:"RepositoryFilter"`
 	RepoFileIntoVersion []FileVersionMap `yaml:"RepoFileIntoVersion"`
+
+	Notifications Notifications `yaml:"Notifications"`
+
+	RegionAliases []RegionAlias `yaml:"RegionAliases"`
+
+	Unlocated Unlocated `yaml:"Unlocated"`
+
+	ASNGroups []ASNGroup `yaml:"ASNGroups"`
+
+	DnfRepos []DnfRepo `yaml:"DnfRepos"`
+
+	Torrent Torrent `yaml:"Torrent"`
+
+	LocalServe LocalServe `yaml:"LocalServe"`
+
+	Metrics Metrics `yaml:"Metrics"`
+
+	HTTPCache HTTPCache `yaml:"HTTPCache"`
+
+	CORS CORS `yaml:"CORS"`
+}
+
+// HTTPCache configures the lifetimes in seconds of the cacheable responses,
+// the clients revalidating them with their ETag on every use when 0
+type HTTPCache struct {
+	// Versions is the lifetime of the version list
+	Versions int `yaml:"Versions"`
+	// FileTrees is the lifetime of the file trees of the versions
+	FileTrees int `yaml:"FileTrees"`
+	// Files is the lifetime of the metadata of the files
+	Files int `yaml:"Files"`
+	// Mirrors is the lifetime of the public fields of the mirrors
+	Mirrors int `yaml:"Mirrors"`
+}
+
+// CORS configures the cross-origin requests allowed on the JSON API
+type CORS struct {
+	// AllowedOrigins are the origins allowed, "*" allowing any origin
+	AllowedOrigins []string `yaml:"AllowedOrigins"`
+	// MaxAge is the lifetime in seconds of the preflight responses
+	MaxAge int `yaml:"MaxAge"`
+}
+
+// Metrics configures the /metrics endpoint exposed in the Prometheus
+// text format
+type Metrics struct {
+	Enabled bool `yaml:"Enabled"`
+	// ListenAddress is a separate listener for the endpoint, the endpoint
+	// being served along with the files when empty
+	ListenAddress string `yaml:"ListenAddress"`
+}
+
+// LocalServe configures the files served by mirrorbits itself from the
+// local repository instead of being redirected to a mirror
+type LocalServe struct {
+	Enabled bool `yaml:"Enabled"`
+	// MaxSize is the size in bytes up to which the files are always served
+	// locally, larger files being only served when no mirror qualifies
+	MaxSize int64 `yaml:"MaxSize"`
+	// Bandwidth is the rate in KB/s shared by all the local transfers, 0
+	// meaning unlimited
+	Bandwidth int `yaml:"Bandwidth"`
+}
+
+// Torrent configures the torrents generated for the files of the repository
+type Torrent struct {
+	// Trackers are announced in the torrents, none by default
+	Trackers []string `yaml:"Trackers"`
+	// WebSeeds is the maximum number of mirrors listed as web seeds
+	WebSeeds int `yaml:"WebSeeds"`
+}
+
+// DnfRepo is the layout of a dnf repository differing from <Name>/<arch>
+// within the directory of a version
+type DnfRepo struct {
+	Name string `yaml:"Name"`
+	// Path is relative to the directory of the version, $arch
+	// being replaced by the architecture
+	Path string `yaml:"Path"`
+}
+
+// ASNGroup is a named set of AS numbers, typically the networks of a carrier
+type ASNGroup struct {
+	Name   string `yaml:"Name"`
+	ASNums []uint `yaml:"ASNums"`
+	// Exclusive restricts the clients of the group to the mirrors of
+	// the group, as long as one of them is available
+	Exclusive bool `yaml:"Exclusive"`
+}
+
+// Unlocated configures the routing of the clients without a GeoIP match
+type Unlocated struct {
+	// Policy is one of random, weighted, region, mirrors or fallbacks
+	Policy string `yaml:"Policy"`
+	// Region is the location assumed for the clients by the region policy
+	Region UnlocatedRegion `yaml:"Region"`
+	// Mirrors are the names of the mirrors used by the mirrors policy
+	Mirrors []string `yaml:"Mirrors"`
+}
+
+// UnlocatedRegion is the default location of the clients without a GeoIP match
+type UnlocatedRegion struct {
+	CountryCode   string  `yaml:"CountryCode"`
+	ContinentCode string  `yaml:"ContinentCode"`
+	Latitude      float32 `yaml:"Latitude"`
+	Longitude     float32 `yaml:"Longitude"`
+}
+
+// Notifications configures the delivery of mirror events to third parties
+type Notifications struct {
+	Webhooks      []Webhook  `yaml:"Webhooks"`
+	SMTP          SMTPServer `yaml:"SMTP"`
+	MaxRetries    int        `yaml:"MaxRetries"`
+	RetryInterval int        `yaml:"RetryInterval"`
+}
+
+// Webhook is an URL receiving the mirror events as signed JSON documents
+type Webhook struct {
+	URL    string   `yaml:"URL"`
+	Secret string   `yaml:"Secret"`
+	Events []string `yaml:"Events"`
+}
+
+// SMTPServer is the mail server used to warn the mirror admins
+type SMTPServer struct {
+	Address         string `yaml:"Address"`
+	Username        string `yaml:"Username"`
+	Password        string `yaml:"Password"`
+	From            string `yaml:"From"`
+	OutageThreshold int    `yaml:"OutageThreshold"`
 }
 
 type ParticularFileMapping struct {
@@ -122,12 +284,24 @@ type FileVersionMap struct {
 	Version  string `yaml:"Version"`
 }
 
+// RegionAlias maps a set of country codes to a single code and display name
+type RegionAlias struct {
+	Codes   []string `yaml:"Codes"`
+	Code    string   `yaml:"Code"`
+	Country string   `yaml:"Country"`
+}
+
+// Fallback is a mirror used when no regular mirror is able to serve a request
 type Fallback struct {
-	URL              string `yaml:"URL"`
-	CountryCode      string `yaml:"CountryCode"`
-	ContinentCode    string `yaml:"ContinentCode"`
-	Name             string `yaml:"Name"`
-	NetworkBandwidth int32  `yaml:"NetworkBandwidth"`
+	URL              string  `yaml:"URL"`
+	CountryCode      string  `yaml:"CountryCode"`
+	ContinentCode    string  `yaml:"ContinentCode"`
+	Country          string  `yaml:"Country"`
+	Name             string  `yaml:"Name"`
+	NetworkBandwidth int32   `yaml:"NetworkBandwidth"`
+	Latitude         float32 `yaml:"Latitude"`
+	Longitude        float32 `yaml:"Longitude"`
+	ASNum            uint    `yaml:"ASNum"`
 }
 
 type sentinels struct {
@@ -181,6 +355,29 @@ func ReloadConfig() error {
 	if c.WeightDistributionRange <= 0 {
 		return fmt.Errorf("WeightDistributionRange must be > 0")
 	}
+	if c.BandwidthWindow <= 0 {
+		return fmt.Errorf("BandwidthWindow must be > 0")
+	}
+	if c.SubdivisionWeight < 0 {
+		return fmt.Errorf("SubdivisionWeight must be >= 0")
+	}
+	if c.BandwidthThreshold < 0 {
+		return fmt.Errorf("BandwidthThreshold must be >= 0")
+	}
+	if !isInSlice(c.SelectionMode, []string{"score", "weighted"}) {
+		return fmt.Errorf("Config: SelectionMode can only be set to 'score' or 'weighted'")
+	}
+	if !isInSlice(c.Unlocated.Policy, []string{"random", "weighted", "region", "mirrors", "fallbacks"}) {
+		return fmt.Errorf("Config: Unlocated Policy can only be set to 'random', 'weighted', 'region', 'mirrors' or 'fallbacks'")
+	}
+	if c.Unlocated.Policy == "region" && c.Unlocated.Region.CountryCode == "" {
+		return fmt.Errorf("Config: Unlocated Region requires a CountryCode")
+	}
+	if c.Unlocated.Policy == "mirrors" && len(c.Unlocated.Mirrors) == 0 {
+		return fmt.Errorf("Config: Unlocated Mirrors can't be empty")
+	}
+	c.Unlocated.Region.CountryCode = strings.ToUpper(c.Unlocated.Region.CountryCode)
+	c.Unlocated.Region.ContinentCode = strings.ToUpper(c.Unlocated.Region.ContinentCode)
 	if !isInSlice(c.OutputMode, []string{"auto", "json", "redirect"}) {
 		return fmt.Errorf("Config: outputMode can only be set to 'auto', 'json' or 'redirect'")
 	}
@@ -194,6 +391,58 @@ func ReloadConfig() error {
 	if c.RepositoryScanInterval < 0 {
 		c.RepositoryScanInterval = 0
 	}
+	if c.SourceScanMaxAge < 0 {
+		c.SourceScanMaxAge = 0
+	}
+	for _, w := range c.Notifications.Webhooks {
+		u, err := url.Parse(w.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("Invalid webhook URL: %s", w.URL)
+		}
+	}
+	if c.Notifications.MaxRetries < 0 {
+		c.Notifications.MaxRetries = 0
+	}
+	if c.Notifications.RetryInterval <= 0 {
+		return fmt.Errorf("Notifications: RetryInterval must be > 0")
+	}
+	if c.Notifications.SMTP.Address != "" && c.Notifications.SMTP.From == "" {
+		return fmt.Errorf("Notifications: SMTP requires a From address")
+	}
+	if c.Notifications.SMTP.OutageThreshold <= 0 {
+		return fmt.Errorf("Notifications: OutageThreshold must be > 0")
+	}
+	for _, g := range c.ASNGroups {
+		if g.Name == "" || len(g.ASNums) == 0 {
+			return fmt.Errorf("ASNGroups: Name and ASNums are mandatory")
+		}
+	}
+	if c.LocalServe.MaxSize < 0 || c.LocalServe.Bandwidth < 0 {
+		return fmt.Errorf("LocalServe: MaxSize and Bandwidth must be >= 0")
+	}
+	if c.HTTPCache.Versions < 0 || c.HTTPCache.FileTrees < 0 || c.HTTPCache.Files < 0 || c.HTTPCache.Mirrors < 0 {
+		return fmt.Errorf("HTTPCache: lifetimes must be >= 0")
+	}
+	if c.CORS.MaxAge < 0 {
+		return fmt.Errorf("CORS: MaxAge must be >= 0")
+	}
+	if c.Torrent.WebSeeds <= 0 {
+		return fmt.Errorf("Torrent: WebSeeds must be > 0")
+	}
+	for _, r := range c.DnfRepos {
+		if r.Name == "" || r.Path == "" {
+			return fmt.Errorf("DnfRepos: Name and Path are mandatory")
+		}
+	}
+	for i, a := range c.RegionAliases {
+		if len(a.Codes) == 0 || a.Code == "" {
+			return fmt.Errorf("RegionAliases: Codes and Code are mandatory")
+		}
+		for j := range a.Codes {
+			c.RegionAliases[i].Codes[j] = strings.ToUpper(a.Codes[j])
+		}
+		c.RegionAliases[i].Code = strings.ToUpper(a.Code)
+	}
 
 	if config != nil &&
 		(c.RedisAddress != config.RedisAddress ||
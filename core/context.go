@@ -13,4 +13,6 @@ const (
 	ContextMirrorID
 	// ContextMirrorName is the key for the variable: MirrorName
 	ContextMirrorName
+	// ContextAllowedRedirectDomains is the key for option: AllowedRedirectDomains
+	ContextAllowedRedirectDomains
 )
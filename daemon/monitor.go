@@ -4,6 +4,7 @@
 package daemon
 
 import (
+	"context"
 	innerErrors "errors"
 	"fmt"
 	"github.com/go-resty/resty/v2"
@@ -12,6 +13,7 @@ import (
 	"net"
 	"net/http"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -22,17 +24,24 @@ import (
 	. "github.com/opensourceways/mirrorbits/config"
 	"github.com/opensourceways/mirrorbits/core"
 	"github.com/opensourceways/mirrorbits/database"
+	"github.com/opensourceways/mirrorbits/metrics"
 	"github.com/opensourceways/mirrorbits/mirrors"
+	"github.com/opensourceways/mirrorbits/network"
 	"github.com/opensourceways/mirrorbits/scan"
 	"github.com/opensourceways/mirrorbits/utils"
 	"github.com/pkg/errors"
 )
 
+// versionChecksPerTick is the number of versions probed on a mirror
+// by each health check
+const versionChecksPerTick = 5
+
 var (
 	healthCheckThreads  = 10
-	errRedirect         = errors.New("Redirect not allowed")
 	errMirrorNotScanned = errors.New("Mirror has not yet been scanned")
-	healthyCheckClient  = resty.New().RemoveProxy().SetHeader("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/128.0.0.0 Safari/537.36")
+	healthyCheckClient  = resty.New().RemoveProxy().
+				SetHeader("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/128.0.0.0 Safari/537.36").
+				SetRedirectPolicy(resty.RedirectPolicyFunc(mirrors.CheckRedirect))
 
 	log = logging.MustGetLogger("main")
 )
@@ -58,9 +67,17 @@ type mirror struct {
 	checking  bool
 	scanning  bool
 	lastCheck time.Time
+	// lastCheckInMaintenance is true if the last check happened during a maintenance window
+	lastCheckInMaintenance bool
+	// versionsOffset is the index of the next versions to probe
+	versionsOffset int
 }
 
 func (m *mirror) NeedHealthCheck(checkInterval int) bool {
+	if m.lastCheckInMaintenance && !m.InMaintenance() {
+		// Check the mirror as soon as the maintenance is over
+		return true
+	}
 	return time.Since(m.lastCheck) > time.Duration(checkInterval)*time.Minute
 }
 
@@ -91,11 +108,44 @@ func NewMonitor(r *database.Redis, c *mirrors.Cache) *monitor {
 
 	SubscribeConfig(m.configNotifier)
 
+	m.registerMetrics()
+
 	rand.Seed(time.Now().UnixNano())
 
 	return m
 }
 
+// registerMetrics registers the health check states of the mirrors,
+// the collectors of another monitor registered before being kept
+func (m *monitor) registerMetrics() {
+	state := func(value func(*mirror) bool) func(emit metrics.Emit) {
+		return func(emit metrics.Emit) {
+			m.mapLock.Lock()
+			defer m.mapLock.Unlock()
+			for _, mir := range m.mirrors {
+				v := 0.0
+				if value(mir) {
+					v = 1
+				}
+				emit(v, mir.Name)
+			}
+		}
+	}
+	labels := []string{"mirror"}
+	for _, err := range []error{
+		metrics.NewGaugeFunc("mirrorbits_mirror_up", "Whether the mirror passed its last health check.", labels,
+			state(func(mir *mirror) bool { return mir.Up })),
+		metrics.NewGaugeFunc("mirrorbits_mirror_enabled", "Whether the mirror is enabled.", labels,
+			state(func(mir *mirror) bool { return mir.Enabled })),
+		metrics.NewGaugeFunc("mirrorbits_mirror_maintenance", "Whether the mirror is within a maintenance window.", labels,
+			state(func(mir *mirror) bool { return mir.InMaintenance() })),
+	} {
+		if err != nil {
+			log.Warningf("Metrics: %s", err)
+		}
+	}
+}
+
 func (m *monitor) Stop() {
 	select {
 	case _, _ = <-m.stop:
@@ -110,23 +160,6 @@ func (m *monitor) Wait() {
 	m.wg.Wait()
 }
 
-// Return an error if the endpoint is an unauthorized redirect
-func checkRedirect(req *http.Request, via []*http.Request) error {
-	redirects := req.Context().Value(core.ContextAllowRedirects).(mirrors.Redirects)
-
-	if redirects.Allowed() {
-		return nil
-	}
-
-	name := req.Context().Value(core.ContextMirrorName)
-	for _, r := range via {
-		if r.URL != nil {
-			log.Warningf("Unauthorized redirection for %s: %s => %s", name, r.URL.String(), req.URL.String())
-		}
-	}
-	return errRedirect
-}
-
 // Main monitor loop
 func (m *monitor) MonitorLoop() {
 	m.wg.Add(1)
@@ -199,6 +232,9 @@ func (m *monitor) MonitorLoop() {
 		m.wg.Add(1)
 		go m.healthCheckLoop()
 	}
+	// Start the fallback health check routine
+	m.wg.Add(1)
+	go m.fallbackCheckLoop()
 	// Start the mirror sync routines
 	for i := 0; i < cnf.ConcurrentSync; i++ {
 		m.wg.Add(1)
@@ -234,6 +270,9 @@ func (m *monitor) MonitorLoop() {
 				m.syncMirrorList(id)
 			}
 		case <-m.configNotifier:
+			if err := mirrors.MigrateRegionAliases(m.redis); err != nil {
+				log.Errorf("Unable to apply the region aliases to the mirrors: %s", err)
+			}
 			if repositoryScanInterval != cnf.RepositoryScanInterval {
 				repositoryScanInterval = cnf.RepositoryScanInterval
 
@@ -357,7 +396,7 @@ func (m *monitor) healthCheckLoop() {
 			mirror = *mptr
 			m.mapLock.Unlock()
 
-			err := m.healthCheck(mirror.Mirror)
+			err := m.healthCheck(&mirror)
 
 			if err == errMirrorNotScanned {
 				// Not removing the 'checking' lock is intended here so the mirror won't
@@ -366,11 +405,13 @@ func (m *monitor) healthCheckLoop() {
 			}
 
 			m.mapLock.Lock()
-			if mirror, ok := m.mirrors[id]; ok {
+			if mptr, ok := m.mirrors[id]; ok {
+				mptr.versionsOffset = mirror.versionsOffset
 				if !database.RedisIsLoading(err) {
-					mirror.lastCheck = time.Now().UTC()
+					mptr.lastCheck = time.Now().UTC()
+					mptr.lastCheckInMaintenance = mptr.InMaintenance()
 				}
-				mirror.checking = false
+				mptr.checking = false
 			}
 			m.mapLock.Unlock()
 		}
@@ -456,7 +497,9 @@ func (m *monitor) syncLoop() {
 }
 
 // Do an actual health check against a given mirror
-func (m *monitor) healthCheck(mirror mirrors.Mirror) error {
+func (m *monitor) healthCheck(mptr *mirror) error {
+	mirror := mptr.Mirror
+
 	// Format log output
 	format := "%-" + fmt.Sprintf("%d.%ds", m.formatLongestID+4, m.formatLongestID+4)
 
@@ -475,18 +518,31 @@ func (m *monitor) healthCheck(mirror mirrors.Mirror) error {
 		return nil
 	}
 
+	// Failures are expected during a maintenance window and must not
+	// change the state of the mirror.
+	inMaintenance := mirror.InMaintenance()
+	markDown := func(reason string) error {
+		if inMaintenance {
+			log.Noticef(format+"In maintenance, ignoring failure: %s", mirror.Name, reason)
+			return nil
+		}
+		return mirrors.MarkMirrorDown(m.redis, mirror.ID, reason)
+	}
+
 	// Prepare the HTTP request
-	head, err := healthyCheckClient.R().Head(utils.ConcatURL(mirror.HttpURL, file))
+	head, err := healthyCheckClient.R().
+		SetContext(mirrors.RedirectContext(context.Background(), &mirror)).
+		Head(utils.ConcatURL(mirror.HttpURL, file))
 	if err != nil {
 		log.Errorf(format+"Unable to http connect to mirror: %s", mirror.Name, err)
 		var opErr *net.OpError
 		if innerErrors.As(err, &opErr) {
 			log.Debugf("Op: %s | Net: %s | Addr: %s | Err: %s | Temporary: %t", opErr.Op, opErr.Net, opErr.Addr, opErr.Error(), opErr.Temporary())
 		}
-		if strings.Contains(err.Error(), errRedirect.Error()) {
-			mirrors.MarkMirrorDown(m.redis, mirror.ID, "Unauthorized redirect")
+		if mirrors.IsRedirectError(err) {
+			markDown("Unauthorized redirect")
 		} else {
-			mirrors.MarkMirrorDown(m.redis, mirror.ID, "Unreachable")
+			markDown("Unreachable")
 		}
 		return err
 	}
@@ -495,6 +551,15 @@ func (m *monitor) healthCheck(mirror mirrors.Mirror) error {
 	contentLength := head.Header().Get("Content-Length")
 	head.Header().Get("Last-Modified")
 
+	if statusCode == http.StatusOK && head.RawResponse != nil && head.RawResponse.Request != nil {
+		resolved := mirrors.ResolvedBaseURL(head.RawResponse.Request.URL, file)
+		if resolved != "" {
+			if err := mirrors.SetMirrorResolvedURL(m.redis, mirror.ID, resolved); err != nil {
+				log.Warningf(format+"Unable to record the resolved URL: %s", mirror.Name, err)
+			}
+		}
+	}
+
 	switch statusCode {
 	case 200:
 		err = mirrors.MarkMirrorUp(m.redis, mirror.ID)
@@ -507,12 +572,13 @@ func (m *monitor) healthCheck(mirror mirrors.Mirror) error {
 		} else {
 			log.Noticef(format+"Up!", mirror.Name)
 		}
+		m.checkVersions(mptr, format)
 	case 404:
-		err = mirrors.MarkMirrorDown(m.redis, mirror.ID, fmt.Sprintf("File not found %s (error 404)", file))
+		err = markDown(fmt.Sprintf("File not found %s (error 404)", file))
 		if err != nil {
 			log.Errorf(format+"Unable to mark mirror as down: %s", mirror.Name, err)
 		}
-		if GetConfig().DisableOnMissingFile {
+		if GetConfig().DisableOnMissingFile && !inMaintenance {
 			err = mirrors.DisableMirror(m.redis, mirror.ID)
 			if err != nil {
 				log.Errorf(format+"Unable to disable mirror: %s", mirror.Name, err)
@@ -520,7 +586,7 @@ func (m *monitor) healthCheck(mirror mirrors.Mirror) error {
 		}
 		log.Errorf(format+"Error: File %s not found (error 404)", mirror.Name, file)
 	default:
-		err = mirrors.MarkMirrorDown(m.redis, mirror.ID, fmt.Sprintf("Got status code %d", statusCode))
+		err = markDown(fmt.Sprintf("Got status code %d", statusCode))
 		if err != nil {
 			log.Errorf(format+"Unable to mark mirror as down: %s", mirror.Name, err)
 		}
@@ -529,14 +595,149 @@ func (m *monitor) healthCheck(mirror mirrors.Mirror) error {
 	return nil
 }
 
+// Periodically check the health of the fallbacks
+func (m *monitor) fallbackCheckLoop() {
+	defer m.wg.Done()
+
+	for {
+		m.checkFallbacks()
+
+		select {
+		case <-m.stop:
+			return
+		case <-time.After(time.Duration(GetConfig().CheckInterval) * time.Minute):
+		}
+	}
+}
+
+// Check the fallbacks against a random file of the local repository, or
+// their root if the repository is unknown. Unlike the mirrors, the state
+// of the fallbacks is only kept in memory.
+func (m *monitor) checkFallbacks() {
+	mlist, excluded := mirrors.GetFallbacks(network.GeoIPRecord{})
+
+	for _, fallback := range append(mlist, excluded...) {
+		if utils.IsStopped(m.stop) {
+			return
+		}
+
+		file, _, err := m.getRandomFileFrom("FILES")
+		if err != nil {
+			file = ""
+		}
+
+		head, err := healthyCheckClient.R().
+			SetContext(mirrors.RedirectContext(context.Background(), &fallback)).
+			Head(utils.ConcatURL(fallback.HttpURL, file))
+
+		var reason string
+		switch {
+		case err != nil && mirrors.IsRedirectError(err):
+			reason = "Unauthorized redirect"
+		case err != nil:
+			reason = "Unreachable"
+		case head.StatusCode() != http.StatusOK:
+			reason = fmt.Sprintf("Got status code %d", head.StatusCode())
+		}
+
+		if reason != "" {
+			if fallback.Up {
+				log.Warningf("Fallback %s is down: %s", fallback.Name, reason)
+			}
+			mirrors.SetFallbackState(fallback.HttpURL, false, reason)
+			continue
+		}
+		if !fallback.Up {
+			log.Noticef("Fallback %s is up", fallback.Name)
+		}
+		mirrors.SetFallbackState(fallback.HttpURL, true, "")
+	}
+}
+
+// Probe the selector file of the next published versions and record
+// which versions are available on the mirror. Only a few versions are
+// probed by each health check, the others keeping their previous state.
+func (m *monitor) checkVersions(mptr *mirror, format string) {
+	mirror := mptr.Mirror
+	selectors := filesystem.GetSelectorList()
+
+	published := make([]string, 0, len(selectors))
+	for version, files := range selectors {
+		if len(files) > 0 {
+			published = append(published, version)
+		}
+	}
+	sort.Strings(published)
+
+	versions := make(mirrors.VersionAvailability)
+	for _, version := range published {
+		if available, ok := mirror.Versions[version]; ok {
+			versions[version] = available
+		}
+	}
+
+	var probed []string
+	probed, mptr.versionsOffset = versionsToCheck(published, mptr.versionsOffset, versionChecksPerTick)
+
+	for _, version := range probed {
+		if utils.IsStopped(m.stop) {
+			return
+		}
+
+		files := selectors[version]
+		file := files[0].Dir + filesystem.Sep + files[0].Name
+		head, err := healthyCheckClient.R().
+			SetContext(mirrors.RedirectContext(context.Background(), &mirror)).
+			Head(utils.ConcatURL(mirror.HttpURL, file))
+
+		status := 0
+		if err == nil {
+			status = head.StatusCode()
+		}
+
+		// Keep the previous state on transient errors
+		switch status {
+		case http.StatusOK:
+			versions[version] = true
+		case http.StatusNotFound:
+			versions[version] = false
+		}
+	}
+
+	if missing := versions.Missing(); len(missing) > 0 {
+		log.Warningf(format+"Missing versions: %s", mirror.Name, strings.Join(missing, ", "))
+	}
+
+	if err := mirrors.SetMirrorVersions(m.redis, mirror.ID, versions); err != nil {
+		log.Errorf(format+"Unable to record the available versions: %s", mirror.Name, err)
+	}
+}
+
+// versionsToCheck returns at most max versions starting at offset, wrapping
+// around the list, along with the offset of the versions to check next
+func versionsToCheck(versions []string, offset, max int) ([]string, int) {
+	if len(versions) <= max {
+		return versions, 0
+	}
+	offset %= len(versions)
+	subset := make([]string, 0, max)
+	for i := 0; i < max; i++ {
+		subset = append(subset, versions[(offset+i)%len(versions)])
+	}
+	return subset, (offset + max) % len(versions)
+}
+
 // Get a random filename known to be served by the given mirror
 func (m *monitor) getRandomFile(id int) (file string, size int64, err error) {
-	sinterKey := fmt.Sprintf("HANDLEDFILES_%d", id)
+	return m.getRandomFileFrom(fmt.Sprintf("HANDLEDFILES_%d", id))
+}
 
+// Get a random filename from the given set of files
+func (m *monitor) getRandomFileFrom(key string) (file string, size int64, err error) {
 	rconn := m.redis.Get()
 	defer rconn.Close()
 
-	file, err = redis.String(rconn.Do("SRANDMEMBER", sinterKey))
+	file, err = redis.String(rconn.Do("SRANDMEMBER", key))
 	if err != nil {
 		return
 	}
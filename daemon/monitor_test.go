@@ -0,0 +1,42 @@
+// Copyright (c) 2014-2019 Ludovic Fauvet
+// Licensed under the MIT license
+
+package daemon
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestVersionsToCheck(t *testing.T) {
+	versions := []string{"a", "b", "c", "d", "e"}
+
+	subset, next := versionsToCheck(versions[:2], 1, 3)
+	if !reflect.DeepEqual(subset, versions[:2]) || next != 0 {
+		t.Fatalf("Expected all the versions, got %v (next %d)", subset, next)
+	}
+
+	// Every version is checked once in turn
+	seen := make(map[string]int)
+	offset := 0
+	for i := 0; i < 5; i++ {
+		subset, offset = versionsToCheck(versions, offset, 2)
+		if len(subset) != 2 {
+			t.Fatalf("Expected 2 versions, got %v", subset)
+		}
+		for _, v := range subset {
+			seen[v]++
+		}
+	}
+	for _, v := range versions {
+		if seen[v] != 2 {
+			t.Fatalf("Expected %s to be checked twice, got %d", v, seen[v])
+		}
+	}
+
+	// The offset is wrapped when versions are removed
+	subset, next = versionsToCheck(versions[:3], 4, 2)
+	if !reflect.DeepEqual(subset, []string{"b", "c"}) || next != 0 {
+		t.Fatalf("Expected [b c], got %v (next %d)", subset, next)
+	}
+}
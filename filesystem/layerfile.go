@@ -3,6 +3,8 @@
 package filesystem
 
 import (
+	"fmt"
+	"hash/fnv"
 	"os"
 	"sort"
 	"strconv"
@@ -28,37 +30,54 @@ const (
 	RepoVersionDirectoryPrefix = "openEuler-"
 	// Scenario is ISO or edge_img, standard iso file extension
 	StandardISOFileExtension = ".iso"
+	// metadata of a dnf repository, relative to the repository directory
+	RepoMetadataFile = "repodata/repomd.xml"
 )
 
 var (
 	fileTree = &FileStore{
-		Mapping:     make(map[string]*LayerFile, EstimateFileNum),
-		SelectorMap: make(map[string]*LayerFile, RepoVersionNum),
-		Root:        LayerFile{},
+		Mapping:      make(map[string]*LayerFile, EstimateFileNum),
+		SelectorMap:  make(map[string]*LayerFile, RepoVersionNum),
+		Signatures:   make(map[string][]string),
+		RepoMetadata: make(map[string]*LayerFile),
+		Root:         LayerFile{},
 	}
 	pathFilter      []string
 	repoVersionList []DisplayRepoVersion
 	repoVersionMap  = make(map[string][]DisplayFileList, RepoVersionNum)
 	selectorList    []*LayerFile
+	// selectorSet holds the path of the files of selectorList
+	selectorSet = make(map[string]bool)
 
 	lock            sync.RWMutex
 	fileTreeReplica = &FileStore{
-		Mapping:     make(map[string]*LayerFile, EstimateFileNum),
-		SelectorMap: make(map[string]*LayerFile, RepoVersionNum),
-		Root:        LayerFile{},
+		Mapping:      make(map[string]*LayerFile, EstimateFileNum),
+		SelectorMap:  make(map[string]*LayerFile, RepoVersionNum),
+		Signatures:   make(map[string][]string),
+		RepoMetadata: make(map[string]*LayerFile),
+		Root:         LayerFile{},
 	}
 
+	// generation is a digest of the content of the file tree, generationTime
+	// being the time it last changed
+	generation     uint64
+	generationTime time.Time
+
+	// extensions of the detached signatures of the files
+	signatureExtensions = []string{".asc", ".sig"}
+
 	log = logging.MustGetLogger("filesystem")
 )
 
 // website display file information structure
 type DisplayFile struct {
-	Name    string
-	Path    string
-	Size    string
-	ShaCode string
-	Type    string
-	Sub     []DisplayFile `json:",omitempty"`
+	Name       string
+	Path       string
+	Size       string
+	ShaCode    string
+	Type       string
+	Signatures []string      `json:",omitempty"`
+	Sub        []DisplayFile `json:",omitempty"`
 }
 
 // website display file menu structure
@@ -80,10 +99,13 @@ type DisplayRepoVersion struct {
 // Root contains tree-structured file information
 // Mapping is a map contains per file information
 // SelectorMap is a map contains some files that supports to use for mirror check
+// Signatures maps a file to the paths of its detached signatures
 type FileStore struct {
-	Mapping     map[string]*LayerFile
-	SelectorMap map[string]*LayerFile
-	Root        LayerFile
+	Mapping      map[string]*LayerFile
+	SelectorMap  map[string]*LayerFile
+	Signatures   map[string][]string
+	RepoMetadata map[string]*LayerFile
+	Root         LayerFile
 }
 
 // file information structure in project
@@ -114,13 +136,54 @@ func UpdateFileTree(filter config.DirFilter) {
 
 	fileTree = fileTreeReplica
 	fileTreeReplica = &FileStore{
-		Mapping:     make(map[string]*LayerFile, len(fileTree.Mapping)<<1),
-		SelectorMap: make(map[string]*LayerFile, len(fileTree.SelectorMap)<<1),
-		Root:        LayerFile{},
+		Mapping:      make(map[string]*LayerFile, len(fileTree.Mapping)<<1),
+		SelectorMap:  make(map[string]*LayerFile, len(fileTree.SelectorMap)<<1),
+		Signatures:   make(map[string][]string, len(fileTree.Signatures)),
+		RepoMetadata: make(map[string]*LayerFile, len(fileTree.RepoMetadata)),
+		Root:         LayerFile{},
 	}
 	repoVersionList = repoVersionList[0:0]
 	selectorList = selectorList[0:0]
 	collectRepoVersionList(filter)
+	selectorSet = make(map[string]bool, len(selectorList))
+	for _, f := range selectorList {
+		selectorSet[f.path()] = true
+	}
+
+	if digest := treeDigest(fileTree, repoVersionList); digest != generation || generationTime.IsZero() {
+		generation = digest
+		generationTime = time.Now()
+	}
+}
+
+// treeDigest returns a digest of the files, signatures and versions of the
+// file tree, independent of the order in which the files were scanned
+func treeDigest(store *FileStore, versions []DisplayRepoVersion) uint64 {
+	var digest uint64
+	h := fnv.New64a()
+	for path, f := range store.Mapping {
+		h.Reset()
+		fmt.Fprintf(h, "%s\x00%s\x00%d\x00%d\x00%s", path, f.Type, f.Size, f.ModTime.UnixNano(), f.Sha256)
+		digest ^= h.Sum64()
+	}
+	for path, signatures := range store.Signatures {
+		h.Reset()
+		fmt.Fprintf(h, "%s\x00%s", path, strings.Join(signatures, "\x00"))
+		digest ^= h.Sum64()
+	}
+	h.Reset()
+	for _, v := range versions {
+		fmt.Fprintf(h, "%+v\x00", v)
+	}
+	return digest ^ h.Sum64()
+}
+
+// GetRepoGeneration returns the generation of the file tree, changing
+// along with its content, and the time it last changed
+func GetRepoGeneration() (uint64, time.Time) {
+	lock.RLock()
+	defer lock.RUnlock()
+	return generation, generationTime
 }
 
 // a file append to the tree-structured files, and return the file information
@@ -135,9 +198,84 @@ func GetRepoFileData(path string) LayerFile {
 	if p, ok := fileTree.Mapping[path]; ok {
 		return *p
 	}
+	if p, ok := fileTree.RepoMetadata[path]; ok {
+		return *p
+	}
 	return LayerFile{}
 }
 
+// GetRepoFiles returns the path of every file found at or below the given
+// path of the repository, and whether this path is a directory
+func GetRepoFiles(path string) (files []string, isDir bool) {
+	lock.RLock()
+	defer lock.RUnlock()
+
+	node, ok := fileTree.Mapping[strings.Trim(path, Sep)]
+	if !ok {
+		return nil, false
+	}
+	if node.Type == "file" {
+		return []string{node.path()}, false
+	}
+	node.collectFiles(&files)
+	return files, true
+}
+
+// GetIndexedFiles returns the files checked on the mirrors that tell the
+// availability of the given path, and whether this path is a directory. The
+// files are the path itself if it is checked, or else the checked files below
+// it or below its closest parent directory containing some, dir being
+// the directory they were collected from.
+func GetIndexedFiles(path string) (files []string, dir string, isDir bool) {
+	lock.RLock()
+	defer lock.RUnlock()
+
+	path = strings.Trim(path, Sep)
+	node, ok := fileTree.Mapping[path]
+	if !ok {
+		return nil, "", false
+	}
+	isDir = node.Type != "file"
+	if !isDir && selectorSet[path] {
+		return []string{path}, "", false
+	}
+	if !isDir {
+		path = parentDir(path)
+	}
+	for path != "" {
+		if node, ok = fileTree.Mapping[path]; ok {
+			node.collectIndexedFiles(&files)
+			if len(files) > 0 {
+				return files, path, isDir
+			}
+		}
+		path = parentDir(path)
+	}
+	return nil, "", isDir
+}
+
+// IsSelectorFile returns true if the file is checked on the mirrors
+func IsSelectorFile(path string) bool {
+	lock.RLock()
+	defer lock.RUnlock()
+	return selectorSet[strings.Trim(path, Sep)]
+}
+
+// parentDir returns the parent directory of a path of the repository
+func parentDir(path string) string {
+	if i := strings.LastIndex(path, Sep); i > 0 {
+		return path[:i]
+	}
+	return ""
+}
+
+// GetRepoEntryCount returns the number of files and directories of the file tree
+func GetRepoEntryCount() int {
+	lock.RLock()
+	defer lock.RUnlock()
+	return len(fileTree.Mapping)
+}
+
 // get the website displayed repo version list
 func GetRepoVersionList() []DisplayRepoVersion {
 	var ans []DisplayRepoVersion
@@ -147,6 +285,58 @@ func GetRepoVersionList() []DisplayRepoVersion {
 	return ans
 }
 
+// CoverageTarget is a scenario and an architecture of a version, along
+// with the files checked on the mirrors to tell its availability
+type CoverageTarget struct {
+	Version  string
+	Scenario string
+	Arch     string
+	Files    []string
+}
+
+// GetCoverageTargets returns the scenarios and architectures of the versions
+// having files checked on the mirrors, sorted by version, scenario and arch
+func GetCoverageTargets() []CoverageTarget {
+	lock.RLock()
+	defer lock.RUnlock()
+
+	var targets []CoverageTarget
+	index := make(map[string]int)
+	seen := make(map[string]bool)
+	for _, f := range selectorList {
+		arr := strings.SplitN(f.Dir, Sep, 4)
+		if len(arr) < 3 || f.Type != "file" || seen[f.path()] {
+			continue
+		}
+		seen[f.path()] = true
+
+		// x86-64 merge to x86_64, as in the version list
+		arch := arr[2]
+		if arch == "x86-64" {
+			arch = "x86_64"
+		}
+		key := arr[0] + Sep + arr[1] + Sep + arch
+		i, ok := index[key]
+		if !ok {
+			i = len(targets)
+			index[key] = i
+			targets = append(targets, CoverageTarget{Version: arr[0], Scenario: arr[1], Arch: arch})
+		}
+		targets[i].Files = append(targets[i].Files, f.path())
+	}
+
+	sort.Slice(targets, func(i, j int) bool {
+		if targets[i].Version != targets[j].Version {
+			return targets[i].Version < targets[j].Version
+		}
+		if targets[i].Scenario != targets[j].Scenario {
+			return targets[i].Scenario < targets[j].Scenario
+		}
+		return targets[i].Arch < targets[j].Arch
+	})
+	return targets
+}
+
 // get a file list that supports to use for mirror check
 func GetSelectorList() map[string][]*LayerFile {
 	ans := make(map[string][]*LayerFile, len(selectorList))
@@ -166,6 +356,18 @@ func GetSelectorList() map[string][]*LayerFile {
 	return ans
 }
 
+// get the repo version a path belongs to, i.e. its top level directory
+func GetRepoVersion(path string) string {
+	path = strings.TrimPrefix(path, Sep)
+	if i := strings.Index(path, Sep); i >= 0 {
+		path = path[:i]
+	}
+	if !strings.HasPrefix(path, RepoVersionDirectoryPrefix) {
+		return ""
+	}
+	return path
+}
+
 // get a repo version file list to display in website
 func GetRepoFileList(version string, cnf *config.Configuration) []DisplayFileList {
 	lock.RLock()
@@ -200,11 +402,11 @@ func GetRepoFileList(version string, cnf *config.Configuration) []DisplayFileLis
 					Scenario: v.ScenarioName,
 					Arch:     v.ArchName,
 				}
-				ans.appendParticularFile(v, cnf.Repository, cnf.Fallbacks[0].URL)
+				ans.appendParticularFile(v, cnf.Repository)
 				repoVersionMap[version] = append(repoVersionMap[version], *ans)
 			} else {
 				ans := &repoVersionMap[version][idx]
-				ans.appendParticularFile(v, cnf.Repository, cnf.Fallbacks[0].URL)
+				ans.appendParticularFile(v, cnf.Repository)
 				repoVersionMap[version][idx] = *ans
 			}
 		}
@@ -251,8 +453,74 @@ func Filter(path string) bool {
 	return false
 }
 
+// IsSignature returns true if the path is a detached signature of a file of the tree
+func IsSignature(path string) bool {
+	for _, ext := range signatureExtensions {
+		if strings.HasSuffix(path, ext) {
+			return Filter(strings.TrimSuffix(path, ext))
+		}
+	}
+	return false
+}
+
+// AddSignature records a detached signature next to the file it signs
+func AddSignature(path string) {
+	for _, ext := range signatureExtensions {
+		if strings.HasSuffix(path, ext) {
+			signed := strings.TrimSuffix(path, ext)
+			fileTreeReplica.Signatures[signed] = append(fileTreeReplica.Signatures[signed], path)
+			return
+		}
+	}
+}
+
+// GetSignatures returns the paths of the detached signatures of a file
+func GetSignatures(path string) []string {
+	lock.RLock()
+	defer lock.RUnlock()
+	return fileTree.Signatures[strings.Trim(path, Sep)]
+}
+
+// IsRepoMetadata returns true if the path is the metadata of a dnf repository
+// of a repo version, those files being indexed without being displayed
+func IsRepoMetadata(path string) bool {
+	return strings.HasPrefix(path, RepoVersionDirectoryPrefix) && strings.HasSuffix(path, Sep+RepoMetadataFile)
+}
+
+// AddRepoMetadata records the metadata of a dnf repository, checked on
+// the mirrors along with the files of the selector list
+func AddRepoMetadata(fd *FileData) {
+	i := strings.LastIndex(fd.Path, Sep)
+	if i < 0 {
+		return
+	}
+	fileTreeReplica.RepoMetadata[fd.Path] = &LayerFile{
+		Dir:     fd.Path[:i],
+		Name:    fd.Path[i+1:],
+		Type:    "file",
+		Size:    fd.Size,
+		ModTime: fd.ModTime,
+	}
+}
+
+// GetRepoMetadataList returns the metadata of the dnf repositories by version
+func GetRepoMetadataList() map[string][]*LayerFile {
+	lock.RLock()
+	defer lock.RUnlock()
+
+	ans := make(map[string][]*LayerFile)
+	for path, f := range fileTree.RepoMetadata {
+		version := strings.SplitN(path, Sep, 2)[0]
+		ans[version] = append(ans[version], f)
+	}
+	for _, files := range ans {
+		sort.Slice(files, func(i, j int) bool { return files[i].Dir < files[j].Dir })
+	}
+	return ans
+}
+
 // add the configured particular file to the website display file menu
-func (d *DisplayFileList) appendParticularFile(p config.ParticularFileMapping, repoPath, fallbackPath string) {
+func (d *DisplayFileList) appendParticularFile(p config.ParticularFileMapping, repoPath string) {
 	for i, v := range p.SourcePath {
 		path := v
 		pathArr := strings.Split(path, Sep)
@@ -274,11 +542,12 @@ func (d *DisplayFileList) appendParticularFile(p config.ParticularFileMapping, r
 		}
 
 		d.Tree = append(d.Tree, DisplayFile{
-			Name:    pathArr[len(pathArr)-1],
-			Path:    path,
-			Size:    viewSize,
-			ShaCode: shaCode,
-			Type:    "file",
+			Name:       pathArr[len(pathArr)-1],
+			Path:       path,
+			Size:       viewSize,
+			ShaCode:    shaCode,
+			Type:       "file",
+			Signatures: fileTree.Signatures[path],
 		})
 	}
 }
@@ -314,27 +583,35 @@ func covertFileSize(arr []byte) int64 {
 	return sizeInt
 }
 
-// set the file information
-func (ft *LayerFile) setFileData(path string, size, modTime []byte, cnf *config.Configuration) *FileData {
-
+// NewFileData returns the information of a file of the repository
+// from its line in the file list
+func NewFileData(path string, size, modTime []byte, cnf *config.Configuration) *FileData {
 	fd := new(FileData)
-	ft.Type = "file"
 	fd.Path = path
-	ft.Size = covertFileSize(size)
-	fd.Size = ft.Size
+	fd.Size = covertFileSize(size)
 	modTime[4] = '-'
 	modTime[7] = '-'
 	lastModTime, err := time.Parse(time.DateTime, string(modTime))
 	if err == nil {
-		ft.ModTime = lastModTime
 		fd.ModTime = lastModTime
-		ft.setRecentFile()
 	}
 	sha256FilePath := strings.ReplaceAll(utils.ConcatURL(cnf.Repository, path), Sep, string(os.PathSeparator)) + FileExtensionSha256
 	data, err1 := os.ReadFile(sha256FilePath)
 	if err1 == nil {
-		ft.Sha256 = strings.Split(string(data), " ")[0]
-		fd.Sha256 = ft.Sha256
+		fd.Sha256 = strings.Split(string(data), " ")[0]
+	}
+	return fd
+}
+
+// set the file information
+func (ft *LayerFile) setFileData(path string, size, modTime []byte, cnf *config.Configuration) *FileData {
+	fd := NewFileData(path, size, modTime, cnf)
+	ft.Type = "file"
+	ft.Size = fd.Size
+	ft.Sha256 = fd.Sha256
+	if !fd.ModTime.IsZero() {
+		ft.ModTime = fd.ModTime
+		ft.setRecentFile()
 	}
 	return fd
 }
@@ -400,6 +677,7 @@ func (ft *LayerFile) toDisplayFile() DisplayFile {
 		df.Size = utils.ReadableSize(ft.Size)
 		df.ShaCode = ft.Sha256
 		df.Type = "file"
+		df.Signatures = fileTree.Signatures[df.Path]
 	} else {
 		df.Type = "dir"
 		for _, sub := range ft.Sub {
@@ -460,6 +738,38 @@ func (ft *LayerFile) dfsEveryFile(file *LayerFile) {
 	}
 }
 
+// path returns the path of the node in the repository
+func (ft *LayerFile) path() string {
+	if ft.Dir == "" {
+		return ft.Name
+	}
+	return ft.Dir + Sep + ft.Name
+}
+
+// collect the path of every file from a tree-structured files node
+func (ft *LayerFile) collectFiles(files *[]string) {
+	if ft.Type == "file" {
+		*files = append(*files, ft.path())
+		return
+	}
+	for _, p := range ft.Sub {
+		p.collectFiles(files)
+	}
+}
+
+// collect the path of every checked file from a tree-structured files node
+func (ft *LayerFile) collectIndexedFiles(files *[]string) {
+	if ft.Type == "file" {
+		if selectorSet[ft.path()] {
+			*files = append(*files, ft.path())
+		}
+		return
+	}
+	for _, p := range ft.Sub {
+		p.collectIndexedFiles(files)
+	}
+}
+
 func (ft *LayerFile) dfsFirstFile() *LayerFile {
 	if ft == nil {
 		return nil
@@ -0,0 +1,98 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2024. All rights reserved.
+// Licensed under the MIT license
+package filesystem
+
+import (
+	"testing"
+
+	"github.com/opensourceways/mirrorbits/config"
+)
+
+func TestSignatures(t *testing.T) {
+	InitPathFilter(config.DirFilter{SecondDir: []string{"ISO"}, ThirdDir: []string{"x86_64"}})
+
+	iso := "openEuler-22.03-LTS/ISO/x86_64/a.iso"
+	for path, expected := range map[string]bool{
+		iso + ".asc":                 true,
+		iso + ".sig":                 true,
+		iso:                          false,
+		iso + ".sha256sum.asc":       false,
+		"other/ISO/x86_64/a.iso.asc": false,
+	} {
+		if IsSignature(path) != expected {
+			t.Fatalf("IsSignature(%s): expected %t", path, expected)
+		}
+	}
+
+	AddSignature(iso + ".asc")
+	AddSignature(iso + ".sig")
+	UpdateFileTree(config.DirFilter{})
+
+	signatures := GetSignatures("/" + iso)
+	if len(signatures) != 2 || signatures[0] != iso+".asc" || signatures[1] != iso+".sig" {
+		t.Fatalf("Unexpected signatures: %v", signatures)
+	}
+
+	f := LayerFile{Dir: "openEuler-22.03-LTS/ISO/x86_64", Name: "a.iso", Type: "file"}
+	if df := f.toDisplayFile(); len(df.Signatures) != 2 {
+		t.Fatalf("Expected the signatures in the file tree, got %v", df.Signatures)
+	}
+}
+
+func TestTreeDigest(t *testing.T) {
+	a := &LayerFile{Name: "a.iso", Size: 1, Type: "file"}
+	b := &LayerFile{Name: "b.iso", Size: 2, Type: "file"}
+
+	store := &FileStore{Mapping: map[string]*LayerFile{"a.iso": a, "b.iso": b}}
+	digest := treeDigest(store, nil)
+
+	reordered := &FileStore{Mapping: map[string]*LayerFile{"b.iso": b, "a.iso": a}}
+	if treeDigest(reordered, nil) != digest {
+		t.Fatalf("The digest must not depend on the order of the files")
+	}
+
+	changed := &FileStore{Mapping: map[string]*LayerFile{"a.iso": a, "b.iso": {Name: "b.iso", Size: 3, Type: "file"}}}
+	if treeDigest(changed, nil) == digest {
+		t.Fatalf("The digest must change along with the files")
+	}
+
+	if treeDigest(store, []DisplayRepoVersion{{Version: "openEuler-22.03-LTS"}}) == digest {
+		t.Fatalf("The digest must change along with the versions")
+	}
+
+	generation, modTime := GetRepoGeneration()
+	UpdateFileTree(config.DirFilter{})
+	if g, m := GetRepoGeneration(); m.IsZero() || (g == generation && !m.Equal(modTime)) {
+		t.Fatalf("The generation must only change along with the file tree")
+	}
+}
+
+func TestGetCoverageTargets(t *testing.T) {
+	lock.Lock()
+	saved := selectorList
+	selectorList = []*LayerFile{
+		{Dir: "openEuler-22.03-LTS/ISO/x86-64", Name: "b.iso", Type: "file"},
+		{Dir: "openEuler-22.03-LTS/ISO/aarch64", Name: "a.iso", Type: "file"},
+		{Dir: "openEuler-22.03-LTS/ISO/x86_64", Name: "a.iso", Type: "file"},
+		{Dir: "openEuler-22.03-LTS/ISO/x86_64", Name: "a.iso", Type: "file"},
+		{Dir: "openEuler-22.03-LTS/ISO/x86_64", Name: "sub", Type: "dir"},
+		{Dir: "openEuler-20.03-LTS", Name: "README", Type: "file"},
+	}
+	lock.Unlock()
+	defer func() {
+		lock.Lock()
+		selectorList = saved
+		lock.Unlock()
+	}()
+
+	targets := GetCoverageTargets()
+	if len(targets) != 2 {
+		t.Fatalf("Expected 2 targets, got %d", len(targets))
+	}
+	if targets[0].Arch != "aarch64" || len(targets[0].Files) != 1 {
+		t.Fatalf("Unexpected first target %+v", targets[0])
+	}
+	if targets[1].Arch != "x86_64" || len(targets[1].Files) != 2 {
+		t.Fatalf("The x86-64 files must merge into x86_64 without duplicates, got %+v", targets[1])
+	}
+}
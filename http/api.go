@@ -0,0 +1,486 @@
+// Copyright (c) 2014-2019 Ludovic Fauvet
+// Licensed under the MIT license
+
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	. "github.com/opensourceways/mirrorbits/config"
+	"github.com/opensourceways/mirrorbits/filesystem"
+	"github.com/opensourceways/mirrorbits/mirrors"
+	"github.com/opensourceways/mirrorbits/network"
+)
+
+const (
+	// apiPrefix is the namespace of the version 1 of the REST API
+	apiPrefix = "/api/v1/"
+
+	// apiDefaultLimit is the number of items returned when no limit is given
+	apiDefaultLimit = 20
+	// apiMaxLimit is the maximum number of items returned at once
+	apiMaxLimit = 100
+)
+
+var (
+	errAPIInvalidLimit  = errors.New("limit must be within [1, 100]")
+	errAPIInvalidOffset = errors.New("offset must be a positive integer")
+)
+
+// APIError is the body of the responses of the API in case of error
+type APIError struct {
+	Error APIErrorDetails
+}
+
+// APIErrorDetails describes an error of the API
+type APIErrorDetails struct {
+	Code    int
+	Message string
+}
+
+// APIPage is a page of a collection returned by the API
+type APIPage struct {
+	Total  int
+	Limit  int
+	Offset int
+	Items  interface{}
+}
+
+// APIFile is the metadata of a file of the repository
+type APIFile struct {
+	filesystem.FileInfo
+	// Signatures are the paths of the detached signatures of the file
+	Signatures []string `json:",omitempty"`
+}
+
+// APIMirror holds the public fields of a mirror
+type APIMirror struct {
+	ID                 int
+	Name               string
+	HttpURL            string
+	RsyncURL           string `json:",omitempty"`
+	FtpURL             string `json:",omitempty"`
+	SponsorName        string `json:",omitempty"`
+	SponsorURL         string `json:",omitempty"`
+	SponsorLogoURL     string `json:",omitempty"`
+	Country            string
+	CountryCodes       []string
+	ContinentCode      string
+	SubdivisionCode    string `json:",omitempty"`
+	Latitude           float32
+	Longitude          float32
+	ASNum              uint
+	Enabled            bool
+	Up                 bool
+	LastSuccessfulSync mirrors.Time
+}
+
+// APISelectedMirror is a mirror selected to serve a path
+type APISelectedMirror struct {
+	APIMirror
+	Rank     int
+	URL      string
+	Distance float32
+}
+
+// APISelection is the result of the selection of the mirrors serving a path
+type APISelection struct {
+	FileInfo   filesystem.FileInfo
+	ClientInfo network.GeoIPRecord
+	Fallback   bool `json:",omitempty"`
+	APIPage
+}
+
+// APICoverage is the coverage matrix, paginated by mirror
+type APICoverage struct {
+	Columns []mirrors.CoverageColumn
+	APIPage
+}
+
+// apiHandler serves the resources of the REST API:
+//
+//	/api/v1/versions                    the published versions
+//	/api/v1/versions/<version>/files    the file tree of a version
+//	/api/v1/files/<path>                the metadata, hashes and signatures of a file
+//	/api/v1/mirrors                     the public mirrors
+//	/api/v1/mirrors/<name>              a public mirror
+//	/api/v1/selection/<path>            the mirrors selected to serve a path
+//	/api/v1/coverage                    the versions carried by the public mirrors
+func (h *HTTP) apiHandler(w http.ResponseWriter, r *http.Request) {
+	h.templates.RLock()
+	ctx := NewContext(w, r, h.templates)
+	h.templates.RUnlock()
+
+	if allowCORS(w, r, GetConfig()) {
+		return
+	}
+
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		apiError(ctx, http.StatusMethodNotAllowed, http.StatusText(http.StatusMethodNotAllowed))
+		return
+	}
+
+	resource := strings.TrimPrefix(r.URL.Path, apiPrefix)
+	switch {
+	case resource == "versions":
+		h.apiVersions(ctx)
+	case strings.HasPrefix(resource, "versions/") && strings.HasSuffix(resource, "/files"):
+		h.apiFileTree(ctx, strings.TrimSuffix(strings.TrimPrefix(resource, "versions/"), "/files"))
+	case strings.HasPrefix(resource, "files/"):
+		h.apiFile(ctx, strings.TrimPrefix(resource, "files"))
+	case resource == "mirrors":
+		h.apiMirrors(ctx)
+	case strings.HasPrefix(resource, "mirrors/"):
+		h.apiMirror(ctx, strings.TrimPrefix(resource, "mirrors/"))
+	case strings.HasPrefix(resource, "selection/"):
+		h.apiSelection(ctx, strings.TrimPrefix(resource, "selection"))
+	case resource == "coverage":
+		h.apiCoverage(ctx)
+	default:
+		apiError(ctx, http.StatusNotFound, "Unknown resource")
+	}
+}
+
+func (h *HTTP) apiVersions(ctx *Context) {
+	limit, offset, err := apiPaging(ctx)
+	if err != nil {
+		apiError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	etag, modTime := sourceETag()
+	if notModified(ctx.ResponseWriter(), ctx.Request(), etag, modTime, GetConfig().HTTPCache.Versions) {
+		return
+	}
+	versions := filesystem.GetRepoVersionList()
+	if versions == nil {
+		versions = []filesystem.DisplayRepoVersion{}
+	}
+	start, end := apiPageBounds(len(versions), limit, offset)
+	apiWrite(ctx, http.StatusOK, APIPage{
+		Total:  len(versions),
+		Limit:  limit,
+		Offset: offset,
+		Items:  versions[start:end],
+	})
+}
+
+func (h *HTTP) apiFileTree(ctx *Context, version string) {
+	limit, offset, err := apiPaging(ctx)
+	if err != nil {
+		apiError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	if filesystem.GetRepoVersion(version) != version {
+		apiError(ctx, http.StatusNotFound, "Unknown version")
+		return
+	}
+	etag, modTime := sourceETag()
+	if notModified(ctx.ResponseWriter(), ctx.Request(), etag, modTime, GetConfig().HTTPCache.FileTrees) {
+		return
+	}
+	tree := filesystem.GetRepoFileList(version, GetConfig())
+	if len(tree) == 0 {
+		apiError(ctx, http.StatusNotFound, "Unknown version")
+		return
+	}
+	start, end := apiPageBounds(len(tree), limit, offset)
+	apiWrite(ctx, http.StatusOK, APIPage{
+		Total:  len(tree),
+		Limit:  limit,
+		Offset: offset,
+		Items:  tree[start:end],
+	})
+}
+
+func (h *HTTP) apiFile(ctx *Context, path string) {
+	urlPath, ok := apiSanitizePath(ctx, path)
+	if !ok {
+		return
+	}
+	files, isDir := filesystem.GetRepoFiles(urlPath)
+	if len(files) == 0 {
+		apiError(ctx, http.StatusNotFound, "No such file")
+		return
+	}
+	if isDir {
+		apiError(ctx, http.StatusBadRequest, "Is a directory")
+		return
+	}
+	etag, modTime := sourceETag()
+	if notModified(ctx.ResponseWriter(), ctx.Request(), etag, modTime, GetConfig().HTTPCache.Files) {
+		return
+	}
+	fileInfo, err := h.cache.GetFileInfo(files[0])
+	if err != nil {
+		apiError(ctx, http.StatusServiceUnavailable, http.StatusText(http.StatusServiceUnavailable))
+		return
+	}
+	apiWrite(ctx, http.StatusOK, APIFile{
+		FileInfo:   fileInfo,
+		Signatures: filesystem.GetSignatures(files[0]),
+	})
+}
+
+func (h *HTTP) apiMirrors(ctx *Context) {
+	limit, offset, err := apiPaging(ctx)
+	if err != nil {
+		apiError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	etag, modTime := h.mirrorsETag()
+	if notModified(ctx.ResponseWriter(), ctx.Request(), etag, modTime, GetConfig().HTTPCache.Mirrors) {
+		return
+	}
+	mlist, err := h.publicMirrors()
+	if err != nil {
+		apiError(ctx, http.StatusServiceUnavailable, "Cannot fetch the list of mirrors")
+		return
+	}
+	start, end := apiPageBounds(len(mlist), limit, offset)
+	apiWrite(ctx, http.StatusOK, APIPage{
+		Total:  len(mlist),
+		Limit:  limit,
+		Offset: offset,
+		Items:  mlist[start:end],
+	})
+}
+
+func (h *HTTP) apiMirror(ctx *Context, name string) {
+	etag, modTime := h.mirrorsETag()
+	if notModified(ctx.ResponseWriter(), ctx.Request(), etag, modTime, GetConfig().HTTPCache.Mirrors) {
+		return
+	}
+	mlist, err := h.publicMirrors()
+	if err != nil {
+		apiError(ctx, http.StatusServiceUnavailable, "Cannot fetch the list of mirrors")
+		return
+	}
+	for _, m := range mlist {
+		if m.Name == name {
+			apiWrite(ctx, http.StatusOK, m)
+			return
+		}
+	}
+	apiError(ctx, http.StatusNotFound, "No such mirror")
+}
+
+func (h *HTTP) apiSelection(ctx *Context, path string) {
+	limit, offset, err := apiPaging(ctx)
+	if err != nil {
+		apiError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	urlPath, ok := apiSanitizePath(ctx, path)
+	if !ok {
+		return
+	}
+
+	remoteIP := clientIP(ctx.Request())
+	if fromip := ctx.QueryParam("fromip"); net.ParseIP(fromip) != nil {
+		remoteIP = fromip
+	}
+	clientInfo := locateUnlocated(h.geoip.GetRecord(remoteIP), GetConfig())
+
+	if files, _ := filesystem.GetRepoFiles(urlPath); len(files) == 0 {
+		apiError(ctx, http.StatusNotFound, "No such file")
+		return
+	}
+
+	fileInfo := filesystem.NewFileInfo(urlPath)
+	mlist, _, err := h.mirrorSelector(ctx, h.cache, &fileInfo, clientInfo)
+	if _, isNetErr := err.(net.Error); isNetErr {
+		apiError(ctx, http.StatusServiceUnavailable, http.StatusText(http.StatusServiceUnavailable))
+		return
+	}
+
+	fallback := len(mlist) == 0
+	if fallback {
+		mlist = mirrors.SelectFallbacks(clientInfo)
+	}
+
+	path = strings.TrimPrefix(fileInfo.Path, "/")
+	start, end := apiPageBounds(len(mlist), limit, offset)
+	selected := make([]APISelectedMirror, 0, end-start)
+	for i, m := range mlist[start:end] {
+		selected = append(selected, APISelectedMirror{
+			APIMirror: apiMirror(m),
+			Rank:      start + i + 1,
+			URL:       m.HttpURL + path,
+			Distance:  m.Distance,
+		})
+	}
+
+	apiWrite(ctx, http.StatusOK, APISelection{
+		FileInfo:   fileInfo,
+		ClientInfo: clientInfo,
+		Fallback:   fallback,
+		APIPage: APIPage{
+			Total:  len(mlist),
+			Limit:  limit,
+			Offset: offset,
+			Items:  selected,
+		},
+	})
+}
+
+func (h *HTTP) apiCoverage(ctx *Context) {
+	limit, offset, err := apiPaging(ctx)
+	if err != nil {
+		apiError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	matrix, err := h.coverageMatrix()
+	if err != nil {
+		apiError(ctx, http.StatusServiceUnavailable, "Cannot compute the coverage")
+		return
+	}
+	rows := filterCoverage(matrix.Rows, ctx.QueryParam("country"))
+	start, end := apiPageBounds(len(rows), limit, offset)
+	uncacheable(ctx.ResponseWriter())
+	apiWrite(ctx, http.StatusOK, APICoverage{
+		Columns: matrix.Columns,
+		APIPage: APIPage{
+			Total:  len(rows),
+			Limit:  limit,
+			Offset: offset,
+			Items:  rows[start:end],
+		},
+	})
+}
+
+// publicMirrors returns the public fields of all the
+// mirrors but the private ones, ordered by ID
+func (h *HTTP) publicMirrors() ([]APIMirror, error) {
+	mirrorsMap, err := h.redis.GetListOfMirrors()
+	if err != nil {
+		return nil, err
+	}
+
+	var mirrorsIDs []int
+	for id := range mirrorsMap {
+		mirrorsIDs = append(mirrorsIDs, id)
+	}
+	sort.Ints(mirrorsIDs)
+
+	mlist := make([]APIMirror, 0, len(mirrorsIDs))
+	for _, id := range mirrorsIDs {
+		m, err := h.cache.GetMirror(id)
+		if err != nil {
+			return nil, err
+		}
+		if m.Private {
+			continue
+		}
+		mlist = append(mlist, apiMirror(m))
+	}
+	return mlist, nil
+}
+
+// apiMirror returns the public fields of the mirror
+func apiMirror(m mirrors.Mirror) APIMirror {
+	return APIMirror{
+		ID:                 m.ID,
+		Name:               m.Name,
+		HttpURL:            m.HttpURL,
+		RsyncURL:           m.RsyncURL,
+		FtpURL:             m.FtpURL,
+		SponsorName:        m.SponsorName,
+		SponsorURL:         m.SponsorURL,
+		SponsorLogoURL:     m.SponsorLogoURL,
+		Country:            m.Country,
+		CountryCodes:       strings.Fields(m.CountryCodes),
+		ContinentCode:      m.ContinentCode,
+		SubdivisionCode:    m.SubdivisionCode,
+		Latitude:           m.Latitude,
+		Longitude:          m.Longitude,
+		ASNum:              m.Asnum,
+		Enabled:            m.Enabled,
+		Up:                 m.Up,
+		LastSuccessfulSync: m.LastSuccessfulSync,
+	}
+}
+
+// apiSanitizePath returns the path of the repository targeted by the
+// request, the error being written to the client if ok is false
+func apiSanitizePath(ctx *Context, path string) (urlPath string, ok bool) {
+	urlPath, err := sanitizePath(path)
+	if err == filesystem.ErrOutsideRepo {
+		apiError(ctx, http.StatusForbidden, http.StatusText(http.StatusForbidden))
+		return "", false
+	} else if err != nil {
+		apiError(ctx, http.StatusNotFound, "No such file")
+		return "", false
+	}
+	return urlPath, true
+}
+
+// apiPaging returns the limit and the offset requested by the client
+func apiPaging(ctx *Context) (limit, offset int, err error) {
+	limit = apiDefaultLimit
+	if v := ctx.QueryParam("limit"); v != "" {
+		limit, err = strconv.Atoi(v)
+		if err != nil || limit < 1 || limit > apiMaxLimit {
+			return 0, 0, errAPIInvalidLimit
+		}
+	}
+	if v := ctx.QueryParam("offset"); v != "" {
+		offset, err = strconv.Atoi(v)
+		if err != nil || offset < 0 {
+			return 0, 0, errAPIInvalidOffset
+		}
+	}
+	return limit, offset, nil
+}
+
+// apiPageBounds returns the bounds of the requested page within a collection
+func apiPageBounds(total, limit, offset int) (start, end int) {
+	if offset > total {
+		offset = total
+	}
+	end = offset + limit
+	if end > total {
+		end = total
+	}
+	return offset, end
+}
+
+func apiError(ctx *Context, code int, message string) {
+	uncacheable(ctx.ResponseWriter())
+	apiWrite(ctx, code, APIError{
+		Error: APIErrorDetails{
+			Code:    code,
+			Message: message,
+		},
+	})
+}
+
+func apiWrite(ctx *Context, code int, v interface{}) {
+	var output []byte
+	var err error
+	if ctx.IsPretty() {
+		output, err = json.MarshalIndent(v, "", "    ")
+	} else {
+		output, err = json.Marshal(v)
+	}
+	if err != nil {
+		http.Error(ctx.ResponseWriter(), err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w := ctx.ResponseWriter()
+	if w.Header().Get("Cache-Control") == "" {
+		w.Header().Set("Cache-Control", "private, no-cache")
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	if w.Header().Get("Content-Encoding") == "" {
+		w.Header().Set("Content-Length", strconv.Itoa(len(output)))
+	}
+	w.WriteHeader(code)
+	w.Write(output)
+}
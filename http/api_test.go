@@ -0,0 +1,105 @@
+// Copyright (c) 2014-2019 Ludovic Fauvet
+// Licensed under the MIT license
+
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	. "github.com/opensourceways/mirrorbits/config"
+	"github.com/opensourceways/mirrorbits/mirrors"
+)
+
+func TestAPIPaging(t *testing.T) {
+	for target, expected := range map[string][2]int{
+		"/api/v1/mirrors":                    {apiDefaultLimit, 0},
+		"/api/v1/mirrors?limit=5&offset=10":  {5, 10},
+		"/api/v1/mirrors?limit=100&offset=0": {100, 0},
+	} {
+		ctx := NewContext(httptest.NewRecorder(), httptest.NewRequest("GET", target, nil), Templates{})
+		limit, offset, err := apiPaging(ctx)
+		if err != nil || limit != expected[0] || offset != expected[1] {
+			t.Fatalf("%s: unexpected paging %d %d %v", target, limit, offset, err)
+		}
+	}
+
+	for _, target := range []string{"/api/v1/mirrors?limit=0", "/api/v1/mirrors?limit=101", "/api/v1/mirrors?offset=-1", "/api/v1/mirrors?limit=a"} {
+		ctx := NewContext(httptest.NewRecorder(), httptest.NewRequest("GET", target, nil), Templates{})
+		if _, _, err := apiPaging(ctx); err == nil {
+			t.Fatalf("%s: expected an error", target)
+		}
+	}
+
+	if start, end := apiPageBounds(10, 5, 8); start != 8 || end != 10 {
+		t.Fatalf("Unexpected bounds %d %d", start, end)
+	}
+	if start, end := apiPageBounds(10, 5, 20); start != 10 || end != 10 {
+		t.Fatalf("Unexpected bounds %d %d", start, end)
+	}
+}
+
+func TestAPIMirror(t *testing.T) {
+	m := apiMirror(mirrors.Mirror{
+		ID:           1,
+		Name:         "m1",
+		HttpURL:      "https://m1.example.org/",
+		CountryCodes: "CN HK",
+		AdminEmail:   "admin@example.org",
+		Enabled:      true,
+	})
+	if m.ID != 1 || m.Name != "m1" || len(m.CountryCodes) != 2 || m.CountryCodes[1] != "HK" || !m.Enabled {
+		t.Fatalf("Unexpected mirror: %+v", m)
+	}
+
+	output, _ := json.Marshal(m)
+	var fields map[string]interface{}
+	json.Unmarshal(output, &fields)
+	if _, ok := fields["AdminEmail"]; ok {
+		t.Fatalf("The admin of the mirror must not be exposed")
+	}
+}
+
+func TestAPIHandler_Errors(t *testing.T) {
+	SetConfiguration(&Configuration{})
+
+	h := &HTTP{}
+	h.templates.RWMutex = new(sync.RWMutex)
+
+	for _, tc := range []struct {
+		method, target string
+		code           int
+	}{
+		{"GET", "/api/v1/unknown", http.StatusNotFound},
+		{"POST", "/api/v1/versions", http.StatusMethodNotAllowed},
+		{"GET", "/api/v1/versions?limit=1000", http.StatusBadRequest},
+		{"GET", "/api/v1/versions/../files", http.StatusNotFound},
+	} {
+		rec := httptest.NewRecorder()
+		h.apiHandler(rec, httptest.NewRequest(tc.method, tc.target, nil))
+		if rec.Code != tc.code {
+			t.Fatalf("%s %s: expected %d, got %d", tc.method, tc.target, tc.code, rec.Code)
+		}
+		var body APIError
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || body.Error.Code != tc.code || body.Error.Message == "" {
+			t.Fatalf("%s %s: unexpected error object %s", tc.method, tc.target, rec.Body.String())
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	h.apiHandler(rec, httptest.NewRequest("GET", "/api/v1/versions", nil))
+	var page APIPage
+	if err := json.Unmarshal(rec.Body.Bytes(), &page); err != nil || rec.Code != http.StatusOK || page.Limit != apiDefaultLimit {
+		t.Fatalf("Unexpected page %d %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestContext_API(t *testing.T) {
+	ctx := NewContext(httptest.NewRecorder(), httptest.NewRequest("GET", "/api/v1/selection/a.iso?mirrorlist", nil), Templates{})
+	if ctx.Type() != API || !ctx.IsAPI() || ctx.IsMirrorlist() {
+		t.Fatalf("Expected an API request")
+	}
+}
@@ -0,0 +1,148 @@
+// Copyright (c) 2014-2019 Ludovic Fauvet
+// Licensed under the MIT license
+
+package http
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	. "github.com/opensourceways/mirrorbits/config"
+	"github.com/opensourceways/mirrorbits/database"
+	"github.com/opensourceways/mirrorbits/mirrors"
+)
+
+/*
+	Bytes redirected to the mirrors, shared by all the nodes of the cluster:
+	STATS_BANDWIDTH_[bucket]			= mirror -> bytes	By slice of bandwidthBucket seconds
+
+	The buckets expire automatically once they get out of the sliding window.
+*/
+
+const (
+	// bandwidthBucket is the duration in seconds of a slice of the sliding window
+	bandwidthBucket = 10
+	// bandwidthRefresh is the interval between two refreshes of the estimations
+	bandwidthRefresh = 5 * time.Second
+)
+
+// bandwidthBucketOf returns the bucket a redirection made at t belongs to
+func bandwidthBucketOf(t time.Time) int64 {
+	return t.Unix() / bandwidthBucket
+}
+
+// bandwidthWindow returns the number of buckets in the sliding window
+func bandwidthWindow() int64 {
+	buckets := int64(GetConfig().BandwidthWindow) / bandwidthBucket
+	if buckets < 1 {
+		buckets = 1
+	}
+	return buckets
+}
+
+// bandwidthTracker estimates the traffic sent to each mirror using the
+// bytes redirected to them during the sliding window
+type bandwidthTracker struct {
+	r     *database.Redis
+	lock  sync.RWMutex
+	rates map[int]int64
+	stop  chan struct{}
+}
+
+func newBandwidthTracker(r *database.Redis) *bandwidthTracker {
+	b := &bandwidthTracker{
+		r:     r,
+		rates: make(map[int]int64),
+		stop:  make(chan struct{}),
+	}
+	go b.refreshLoop()
+	return b
+}
+
+// Stop terminates the refresh of the estimations
+func (b *bandwidthTracker) Stop() {
+	close(b.stop)
+}
+
+func (b *bandwidthTracker) refreshLoop() {
+	ticker := time.NewTicker(bandwidthRefresh)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-b.stop:
+			return
+		case <-ticker.C:
+			if err := b.refresh(); err != nil && !b.r.Failure() {
+				log.Warningf("Bandwidth: unable to refresh the estimations: %s", err)
+			}
+		}
+	}
+}
+
+// refresh computes the average rate of each mirror over the sliding window
+func (b *bandwidthTracker) refresh() error {
+	rconn := b.r.Get()
+	defer rconn.Close()
+
+	window := bandwidthWindow()
+	last := bandwidthBucketOf(time.Now())
+
+	rconn.Send("MULTI")
+	for bucket := last - window + 1; bucket <= last; bucket++ {
+		rconn.Send("HGETALL", fmt.Sprintf("STATS_BANDWIDTH_%d", bucket))
+	}
+	res, err := redis.Values(rconn.Do("EXEC"))
+	if err != nil {
+		return err
+	}
+
+	totals := make(map[int]int64)
+	for _, r := range res {
+		values, err := redis.Int64Map(r, nil)
+		if err != nil {
+			return err
+		}
+		for id, bytes := range values {
+			mirrorID, err := strconv.Atoi(id)
+			if err != nil {
+				continue
+			}
+			totals[mirrorID] += bytes
+		}
+	}
+
+	rates := make(map[int]int64, len(totals))
+	for id, bytes := range totals {
+		rates[id] = bytes / (window * bandwidthBucket)
+	}
+
+	b.lock.Lock()
+	b.rates = rates
+	b.lock.Unlock()
+	return nil
+}
+
+// Rate returns the estimated traffic sent to the mirror in bytes per second
+func (b *bandwidthTracker) Rate(id int) int64 {
+	if b == nil {
+		return 0
+	}
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+	return b.rates[id]
+}
+
+// Utilization returns the estimated fraction of the declared bandwidth
+// of the mirror in use, or zero if the mirror has no declared bandwidth.
+func (b *bandwidthTracker) Utilization(m *mirrors.Mirror) float32 {
+	if m.NetworkBandwidth <= 0 {
+		return 0
+	}
+	// The declared bandwidth is in Mbit/s
+	capacity := float64(m.NetworkBandwidth) * 1000 * 1000 / 8
+	return float32(float64(b.Rate(m.ID)) / capacity)
+}
@@ -0,0 +1,85 @@
+// Copyright (c) 2014-2019 Ludovic Fauvet
+// Licensed under the MIT license
+
+package http
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/opensourceways/mirrorbits/config"
+	"github.com/opensourceways/mirrorbits/filesystem"
+	"github.com/opensourceways/mirrorbits/mirrors"
+	"github.com/opensourceways/mirrorbits/network"
+	. "github.com/opensourceways/mirrorbits/testing"
+)
+
+func TestBandwidthTracker_Refresh(t *testing.T) {
+	mock, conn := PrepareRedisTest()
+
+	SetConfiguration(&Configuration{
+		BandwidthWindow: 20,
+	})
+
+	mock.Command("MULTI").Expect("OK")
+	mock.GenericCommand("HGETALL").Expect([]interface{}{})
+	mock.Command("EXEC").Expect([]interface{}{
+		[]interface{}{[]byte("1"), []byte("25000000"), []byte("2"), []byte("1000")},
+		[]interface{}{[]byte("1"), []byte("225000000")},
+	})
+
+	b := &bandwidthTracker{r: conn, rates: make(map[int]int64)}
+	if err := b.refresh(); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	// 250MB over 20 seconds
+	if rate := b.Rate(1); rate != 12500000 {
+		t.Fatalf("Expected a rate of 12500000 bytes/s, got %d", rate)
+	}
+	if rate := b.Rate(2); rate != 50 {
+		t.Fatalf("Expected a rate of 50 bytes/s, got %d", rate)
+	}
+
+	// 12.5MB/s is 100Mbit/s
+	if u := b.Utilization(&mirrors.Mirror{ID: 1, NetworkBandwidth: 200}); u != 0.5 {
+		t.Fatalf("Expected a utilization of 0.5, got %f", u)
+	}
+	if u := b.Utilization(&mirrors.Mirror{ID: 1}); u != 0 {
+		t.Fatalf("Mirrors without declared bandwidth must have no utilization, got %f", u)
+	}
+}
+
+func TestDefaultEngine_BandwidthSteering(t *testing.T) {
+	cnf := &Configuration{
+		WeightDistributionRange: 1.5,
+		SelectionMode:           "score",
+		BandwidthThreshold:      0.8,
+	}
+	clientInfo := network.GeoIPRecord{CountryCode: "FR", ContinentCode: "EU"}
+	fileInfo := &filesystem.FileInfo{Path: "/openEuler-22.03-LTS"}
+	ctx := NewContext(httptest.NewRecorder(), httptest.NewRequest("GET", "/openEuler-22.03-LTS", nil), Templates{})
+
+	// The mirror "far" (10Gbit/s) is using 90% of its bandwidth
+	engine := DefaultEngine{
+		bandwidth: &bandwidthTracker{rates: map[int]int64{3: 1125000000}},
+	}
+
+	mlist, _, err := engine.Selection(ctx, fileInfo, clientInfo, selectionTestMirrors(), cnf)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if mlist[2].Name != "far" || mlist[2].Utilization != 0.9 {
+		t.Fatalf("Overloaded mirrors must come last, got %s", mlist[2].Name)
+	}
+	if mlist[0].Name != "near-small" || mlist[1].Name != "near-big" {
+		t.Fatalf("The order of the other mirrors must be preserved")
+	}
+
+	// Steering is disabled without threshold
+	cnf.BandwidthThreshold = 0
+	mlist, _, _ = engine.Selection(ctx, fileInfo, clientInfo, selectionTestMirrors(), cnf)
+	if mlist[0].Name != "far" {
+		t.Fatalf("Expected far to come first, got %s", mlist[0].Name)
+	}
+}
@@ -0,0 +1,143 @@
+// Copyright (c) 2014-2019 Ludovic Fauvet
+// Licensed under the MIT license
+
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	. "github.com/opensourceways/mirrorbits/config"
+	"github.com/opensourceways/mirrorbits/filesystem"
+)
+
+// sourceETag returns the entity tag and the modification time of the
+// responses derived from the file tree of the local repository, none
+// before the first scan
+func sourceETag() (string, time.Time) {
+	generation, modTime := filesystem.GetRepoGeneration()
+	if modTime.IsZero() {
+		return "", modTime
+	}
+	return fmt.Sprintf("W/\"s%x\"", generation), modTime
+}
+
+// mirrorsETag returns the entity tag and the modification time of the
+// responses derived from the state of the mirrors, none while their
+// version is unknown
+func (h *HTTP) mirrorsETag() (string, time.Time) {
+	version, modTime := h.cache.MirrorsVersion()
+	if version == 0 {
+		return "", modTime
+	}
+	return fmt.Sprintf("W/\"m%x\"", version), modTime
+}
+
+// cacheControl returns the Cache-Control of a shared response of the
+// given lifetime, 0 requiring a revalidation on every use
+func cacheControl(maxAge int) string {
+	if maxAge <= 0 {
+		return "public, no-cache"
+	}
+	return "public, max-age=" + strconv.Itoa(maxAge)
+}
+
+// notModified sets the validators and the lifetime of a response and
+// answers 304 if the client holds the current representation. Responses
+// without an entity tag are not cached.
+func notModified(w http.ResponseWriter, r *http.Request, etag string, modTime time.Time, maxAge int) bool {
+	if etag == "" {
+		return false
+	}
+	header := w.Header()
+	header.Set("ETag", etag)
+	if !modTime.IsZero() {
+		header.Set("Last-Modified", modTime.UTC().Format(http.TimeFormat))
+	}
+	header.Set("Cache-Control", cacheControl(maxAge))
+
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return false
+	}
+	// If-None-Match takes precedence over If-Modified-Since (RFC 7232 §6)
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		if !etagMatches(inm, etag) {
+			return false
+		}
+	} else if ims := r.Header.Get("If-Modified-Since"); ims != "" && !modTime.IsZero() {
+		t, err := http.ParseTime(ims)
+		if err != nil || modTime.Truncate(time.Second).After(t) {
+			return false
+		}
+	} else {
+		return false
+	}
+
+	w = identityWriter(w)
+	header.Del("Content-Type")
+	header.Del("Content-Length")
+	w.WriteHeader(http.StatusNotModified)
+	return true
+}
+
+// uncacheable removes the validators of a response that can't be cached
+func uncacheable(w http.ResponseWriter) {
+	w.Header().Del("ETag")
+	w.Header().Del("Last-Modified")
+	w.Header().Set("Cache-Control", "private, no-cache")
+}
+
+// etagMatches returns true if the entity tag matches one of the
+// If-None-Match list using the weak comparison
+func etagMatches(list, etag string) bool {
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, e := range strings.Split(list, ",") {
+		e = strings.TrimSpace(e)
+		if e == "*" || strings.TrimPrefix(e, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// allowCORS sets the CORS headers of the responses to the allowed origins
+// and returns true if the request was a preflight request it answered
+func allowCORS(w http.ResponseWriter, r *http.Request, cnf *Configuration) bool {
+	origin := r.Header.Get("Origin")
+
+	allowed := ""
+	for _, o := range cnf.CORS.AllowedOrigins {
+		if o == "*" {
+			allowed = "*"
+			break
+		} else if origin != "" && strings.EqualFold(o, origin) {
+			allowed = origin
+		}
+	}
+
+	header := w.Header()
+	if len(cnf.CORS.AllowedOrigins) > 0 && allowed != "*" {
+		// The response depends on the origin, even if it is rejected or absent
+		header.Add("Vary", "Origin")
+	}
+	if allowed == "" || origin == "" {
+		return false
+	}
+	header.Set("Access-Control-Allow-Origin", allowed)
+
+	if r.Method != http.MethodOptions || r.Header.Get("Access-Control-Request-Method") == "" {
+		header.Set("Access-Control-Expose-Headers", "ETag")
+		return false
+	}
+
+	header.Set("Access-Control-Allow-Methods", "GET, HEAD")
+	header.Set("Access-Control-Allow-Headers", "Accept, If-None-Match, If-Modified-Since")
+	if cnf.CORS.MaxAge > 0 {
+		header.Set("Access-Control-Max-Age", strconv.Itoa(cnf.CORS.MaxAge))
+	}
+	w.WriteHeader(http.StatusNoContent)
+	return true
+}
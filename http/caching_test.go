@@ -0,0 +1,130 @@
+// Copyright (c) 2014-2019 Ludovic Fauvet
+// Licensed under the MIT license
+
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/opensourceways/mirrorbits/config"
+)
+
+func TestNotModified(t *testing.T) {
+	modTime := time.Date(2024, 8, 8, 11, 1, 29, 500, time.UTC)
+	etag := "W/\"s1234\""
+
+	for _, tc := range []struct {
+		name    string
+		method  string
+		headers map[string]string
+		code    int
+	}{
+		{"no validator", "GET", nil, http.StatusOK},
+		{"matching etag", "GET", map[string]string{"If-None-Match": "\"s1234\""}, http.StatusNotModified},
+		{"listed etag", "HEAD", map[string]string{"If-None-Match": "W/\"s1\", W/\"s1234\""}, http.StatusNotModified},
+		{"any etag", "GET", map[string]string{"If-None-Match": "*"}, http.StatusNotModified},
+		{"other etag", "GET", map[string]string{"If-None-Match": "W/\"s1\""}, http.StatusOK},
+		{"etag precedence", "GET", map[string]string{"If-None-Match": "W/\"s1\"", "If-Modified-Since": modTime.Format(http.TimeFormat)}, http.StatusOK},
+		{"not modified since", "GET", map[string]string{"If-Modified-Since": modTime.Format(http.TimeFormat)}, http.StatusNotModified},
+		{"modified since", "GET", map[string]string{"If-Modified-Since": modTime.Add(-time.Hour).Format(http.TimeFormat)}, http.StatusOK},
+		{"unsafe method", "POST", map[string]string{"If-None-Match": etag}, http.StatusOK},
+	} {
+		r := httptest.NewRequest(tc.method, "/api/v1/versions", nil)
+		for k, v := range tc.headers {
+			r.Header.Set(k, v)
+		}
+		rec := httptest.NewRecorder()
+		if !notModified(rec, r, etag, modTime, 300) {
+			rec.WriteHeader(http.StatusOK)
+		}
+		if rec.Code != tc.code {
+			t.Fatalf("%s: expected %d, got %d", tc.name, tc.code, rec.Code)
+		}
+		if rec.Header().Get("ETag") != etag || rec.Header().Get("Last-Modified") != modTime.Format(http.TimeFormat) {
+			t.Fatalf("%s: missing validators %v", tc.name, rec.Header())
+		}
+		if cc := rec.Header().Get("Cache-Control"); cc != "public, max-age=300" {
+			t.Fatalf("%s: unexpected Cache-Control %q", tc.name, cc)
+		}
+	}
+
+	if cc := cacheControl(0); cc != "public, no-cache" {
+		t.Fatalf("Unexpected Cache-Control %q", cc)
+	}
+
+	rec := httptest.NewRecorder()
+	if notModified(rec, httptest.NewRequest("GET", "/", nil), "", time.Time{}, 300) || rec.Header().Get("Cache-Control") != "" {
+		t.Fatalf("Responses without an entity tag must not be cached")
+	}
+}
+
+func TestAllowCORS(t *testing.T) {
+	cnf := &Configuration{
+		CORS: CORS{AllowedOrigins: []string{"https://www.example.org"}, MaxAge: 600},
+	}
+
+	// Not allowed
+	r := httptest.NewRequest("GET", "/api/v1/versions", nil)
+	r.Header.Set("Origin", "https://evil.example.org")
+	rec := httptest.NewRecorder()
+	if allowCORS(rec, r, cnf) || rec.Header().Get("Access-Control-Allow-Origin") != "" {
+		t.Fatalf("The origin must not be allowed")
+	}
+	if rec.Header().Get("Vary") != "Origin" {
+		t.Fatalf("The rejected responses must vary on the origin")
+	}
+
+	// No origin
+	r = httptest.NewRequest("GET", "/api/v1/versions", nil)
+	rec = httptest.NewRecorder()
+	if allowCORS(rec, r, cnf) || rec.Header().Get("Access-Control-Allow-Origin") != "" || rec.Header().Get("Vary") != "Origin" {
+		t.Fatalf("Unexpected CORS headers %v", rec.Header())
+	}
+
+	// Simple request
+	r = httptest.NewRequest("GET", "/api/v1/versions", nil)
+	r.Header.Set("Origin", "https://www.example.org")
+	rec = httptest.NewRecorder()
+	if allowCORS(rec, r, cnf) {
+		t.Fatalf("Simple requests must be handled by the API")
+	}
+	if rec.Header().Get("Access-Control-Allow-Origin") != "https://www.example.org" || rec.Header().Get("Vary") != "Origin" {
+		t.Fatalf("Unexpected CORS headers %v", rec.Header())
+	}
+	if rec.Header().Get("Access-Control-Expose-Headers") != "ETag" {
+		t.Fatalf("The ETag must be exposed")
+	}
+
+	// Preflight request
+	r = httptest.NewRequest("OPTIONS", "/api/v1/versions", nil)
+	r.Header.Set("Origin", "https://www.example.org")
+	r.Header.Set("Access-Control-Request-Method", "GET")
+	rec = httptest.NewRecorder()
+	if !allowCORS(rec, r, cnf) || rec.Code != http.StatusNoContent {
+		t.Fatalf("Expected the preflight request to be answered")
+	}
+	if rec.Header().Get("Access-Control-Allow-Methods") != "GET, HEAD" || rec.Header().Get("Access-Control-Max-Age") != "600" {
+		t.Fatalf("Unexpected preflight headers %v", rec.Header())
+	}
+
+	// Any origin
+	cnf.CORS.AllowedOrigins = []string{"*"}
+	r = httptest.NewRequest("GET", "/api/v1/versions", nil)
+	r.Header.Set("Origin", "https://other.example.org")
+	rec = httptest.NewRecorder()
+	allowCORS(rec, r, cnf)
+	if rec.Header().Get("Access-Control-Allow-Origin") != "*" || rec.Header().Get("Vary") != "" {
+		t.Fatalf("Unexpected CORS headers %v", rec.Header())
+	}
+
+	// No CORS
+	cnf.CORS.AllowedOrigins = nil
+	rec = httptest.NewRecorder()
+	allowCORS(rec, r, cnf)
+	if rec.Header().Get("Access-Control-Allow-Origin") != "" || rec.Header().Get("Vary") != "" {
+		t.Fatalf("Unexpected CORS headers %v", rec.Header())
+	}
+}
@@ -21,12 +21,25 @@ const (
 	FILESTATS
 	MIRRORSTATS
 	CHECKSUM
+	EXPLAIN
+	METALINK
+	API
+	TORRENT
+	SHA256SUMS
+	COVERAGE
 
 	UNDEFINED SecureOption = iota
 	WITHTLS
 	WITHOUTTLS
 )
 
+// metalinkSuffixes are the extensions appended to the path
+// of a file to request its metalink
+var metalinkSuffixes = []string{".meta4", ".metalink"}
+
+// torrentSuffix is the extension appended to the path of a file to request its torrent
+const torrentSuffix = ".torrent"
+
 // Context represents the context of a request
 type Context struct {
 	r             *http.Request
@@ -38,7 +51,14 @@ type Context struct {
 	isMirrorStats bool
 	isFileStats   bool
 	isChecksum    bool
+	isExplain     bool
+	isMetalink    bool
+	isAPI         bool
+	isTorrent     bool
+	isSha256Sums  bool
+	isCoverage    bool
 	isPretty      bool
+	suffix        string
 	secureOption  SecureOption
 }
 
@@ -46,7 +66,10 @@ type Context struct {
 func NewContext(w http.ResponseWriter, r *http.Request, t Templates) *Context {
 	c := &Context{r: r, w: w, t: t, v: r.URL.Query()}
 
-	if c.paramBool("mirrorlist") {
+	if strings.HasPrefix(r.URL.Path, apiPrefix) {
+		c.typ = API
+		c.isAPI = true
+	} else if c.paramBool("mirrorlist") {
 		c.typ = MIRRORLIST
 		c.isMirrorList = true
 	} else if c.paramBool("stats") {
@@ -55,9 +78,24 @@ func NewContext(w http.ResponseWriter, r *http.Request, t Templates) *Context {
 	} else if c.paramBool("mirrorstats") {
 		c.typ = MIRRORSTATS
 		c.isMirrorStats = true
+	} else if c.paramBool("coverage") {
+		c.typ = COVERAGE
+		c.isCoverage = true
+	} else if c.paramBool("sha256sums") {
+		c.typ = SHA256SUMS
+		c.isSha256Sums = true
 	} else if c.paramBool("md5") || c.paramBool("sha1") || c.paramBool("sha256") {
 		c.typ = CHECKSUM
 		c.isChecksum = true
+	} else if c.paramBool("explain") {
+		c.typ = EXPLAIN
+		c.isExplain = true
+	} else if c.paramBool("metalink") || c.trimSuffix(metalinkSuffixes...) || acceptsMetalink(r) {
+		c.typ = METALINK
+		c.isMetalink = true
+	} else if c.trimSuffix(torrentSuffix) {
+		c.typ = TORRENT
+		c.isTorrent = true
 	} else {
 		c.typ = STANDARD
 	}
@@ -119,11 +157,41 @@ func (c *Context) IsMirrorStats() bool {
 	return c.isMirrorStats
 }
 
+// IsCoverage returns true if the coverage matrix of the versions has been requested
+func (c *Context) IsCoverage() bool {
+	return c.isCoverage
+}
+
 // IsChecksum returns true if a checksum has been requested
 func (c *Context) IsChecksum() bool {
 	return c.isChecksum
 }
 
+// IsSha256Sums returns true if the SHA256SUMS of a directory has been requested
+func (c *Context) IsSha256Sums() bool {
+	return c.isSha256Sums
+}
+
+// IsExplain returns true if the explanation of the selection has been requested
+func (c *Context) IsExplain() bool {
+	return c.isExplain
+}
+
+// IsMetalink returns true if the metalink of the file has been requested
+func (c *Context) IsMetalink() bool {
+	return c.isMetalink
+}
+
+// IsTorrent returns true if the torrent of the file has been requested
+func (c *Context) IsTorrent() bool {
+	return c.isTorrent
+}
+
+// IsAPI returns true if the request targets the REST API
+func (c *Context) IsAPI() bool {
+	return c.isAPI
+}
+
 // IsPretty returns true if the pretty json has been requested
 func (c *Context) IsPretty() bool {
 	return c.isPretty
@@ -134,6 +202,12 @@ func (c *Context) QueryParam(key string) string {
 	return c.v.Get(key)
 }
 
+// Path returns the path of the request without
+// the suffix used to select the type of the request
+func (c *Context) Path() string {
+	return strings.TrimSuffix(c.r.URL.Path, c.suffix)
+}
+
 // SecureOption returns the selected secure option
 func (c *Context) SecureOption() SecureOption {
 	return c.secureOption
@@ -143,3 +217,19 @@ func (c *Context) paramBool(key string) bool {
 	_, ok := c.v[key]
 	return ok
 }
+
+// trimSuffix records which of the suffixes ends the
+// path of the request, if any, and returns true if found
+func (c *Context) trimSuffix(suffixes ...string) bool {
+	for _, suffix := range suffixes {
+		if strings.HasSuffix(c.r.URL.Path, suffix) {
+			c.suffix = suffix
+			return true
+		}
+	}
+	return false
+}
+
+func acceptsMetalink(r *http.Request) bool {
+	return strings.Contains(r.Header.Get("Accept"), "application/metalink4+xml")
+}
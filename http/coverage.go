@@ -0,0 +1,133 @@
+// Copyright (c) 2014-2019 Ludovic Fauvet
+// Licensed under the MIT license
+
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	. "github.com/opensourceways/mirrorbits/config"
+	"github.com/opensourceways/mirrorbits/filesystem"
+	"github.com/opensourceways/mirrorbits/mirrors"
+)
+
+// coverageTTL is the maximum lifetime of the computed matrix
+const coverageTTL = time.Minute
+
+// CoveragePage contains the values needed to generate the coverage page
+type CoveragePage struct {
+	mirrors.CoverageMatrix
+	Country     string
+	LocalJSPath string
+}
+
+// coverageMemo holds the last computed matrix, computed again when the
+// file tree or the state of the mirrors changed or after coverageTTL
+type coverageMemo struct {
+	sync.Mutex
+	key      string
+	computed time.Time
+	matrix   *mirrors.CoverageMatrix
+}
+
+// coverageHandler renders the coverage matrix, i.e. /?coverage[&country=CN]
+func (h *HTTP) coverageHandler(w http.ResponseWriter, r *http.Request, ctx *Context) {
+	matrix, err := h.coverageMatrix()
+	if err != nil {
+		log.Errorf("Unable to compute the coverage: %s", err)
+		http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+		return
+	}
+	country := strings.ToUpper(ctx.QueryParam("country"))
+	filtered := mirrors.CoverageMatrix{
+		Columns: matrix.Columns,
+		Rows:    filterCoverage(matrix.Rows, country),
+	}
+
+	w.Header().Set("Cache-Control", "private, no-cache")
+
+	accept := r.Header.Get("Accept")
+	if GetConfig().OutputMode == "json" || strings.Index(accept, "application/json") >= 0 {
+		var output []byte
+		if ctx.IsPretty() {
+			output, err = json.MarshalIndent(filtered, "", "    ")
+		} else {
+			output, err = json.Marshal(filtered)
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.Write(output)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	err = ctx.Templates().coverage.ExecuteTemplate(w, "base", CoveragePage{filtered, country, GetConfig().LocalJSPath})
+	if err != nil {
+		log.Errorf("HTTP error: %s", err.Error())
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// coverageMatrix returns the coverage matrix of the public mirrors
+func (h *HTTP) coverageMatrix() (*mirrors.CoverageMatrix, error) {
+	sourceTag, _ := sourceETag()
+	mirrorsTag, _ := h.mirrorsETag()
+	key := sourceTag + mirrorsTag
+
+	h.coverage.Lock()
+	defer h.coverage.Unlock()
+	if h.coverage.matrix != nil && h.coverage.key == key && time.Since(h.coverage.computed) < coverageTTL {
+		return h.coverage.matrix, nil
+	}
+
+	matrix, err := h.computeCoverage(filesystem.GetCoverageTargets(), GetConfig())
+	if err != nil {
+		return nil, err
+	}
+	h.coverage.key = key
+	h.coverage.computed = time.Now()
+	h.coverage.matrix = matrix
+	return matrix, nil
+}
+
+// computeCoverage computes the states of the targets on the public mirrors
+func (h *HTTP) computeCoverage(targets []filesystem.CoverageTarget, cnf *Configuration) (*mirrors.CoverageMatrix, error) {
+	mirrorsMap, err := h.redis.GetListOfMirrors()
+	if err != nil {
+		return nil, err
+	}
+	var mirrorsIDs []int
+	for id := range mirrorsMap {
+		mirrorsIDs = append(mirrorsIDs, id)
+	}
+	sort.Ints(mirrorsIDs)
+
+	// The index of a mirror not scanned for three intervals is outdated
+	staleAfter := 3 * time.Duration(cnf.ScanInterval) * time.Minute
+	return h.cache.Coverage(targets, mirrorsIDs, staleAfter, time.Now())
+}
+
+// filterCoverage returns the rows of the mirrors serving the given country, if any
+func filterCoverage(rows []mirrors.CoverageRow, country string) []mirrors.CoverageRow {
+	if country == "" {
+		return rows
+	}
+	filtered := []mirrors.CoverageRow{}
+	for _, row := range rows {
+		for _, c := range row.CountryCodes {
+			if strings.EqualFold(c, country) {
+				filtered = append(filtered, row)
+				break
+			}
+		}
+	}
+	return filtered
+}
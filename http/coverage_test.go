@@ -0,0 +1,44 @@
+// Copyright (c) 2014-2019 Ludovic Fauvet
+// Licensed under the MIT license
+
+package http
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/opensourceways/mirrorbits/config"
+	"github.com/opensourceways/mirrorbits/mirrors"
+)
+
+func TestFilterCoverage(t *testing.T) {
+	rows := []mirrors.CoverageRow{
+		{Name: "m1", CountryCodes: []string{"CN", "HK"}},
+		{Name: "m2", CountryCodes: []string{"FR"}},
+		{Name: "m3"},
+	}
+
+	if len(filterCoverage(rows, "")) != 3 {
+		t.Fatalf("All the rows must be kept without a country")
+	}
+	filtered := filterCoverage(rows, "hk")
+	if len(filtered) != 1 || filtered[0].Name != "m1" {
+		t.Fatalf("Expected m1, got %+v", filtered)
+	}
+	if filtered = filterCoverage(rows, "US"); filtered == nil || len(filtered) != 0 {
+		t.Fatalf("Expected no rows, got %+v", filtered)
+	}
+}
+
+func TestContextCoverage(t *testing.T) {
+	SetConfiguration(&Configuration{})
+
+	req := httptest.NewRequest("GET", "/?coverage&country=CN", nil)
+	ctx := NewContext(httptest.NewRecorder(), req, Templates{})
+	if !ctx.IsCoverage() || ctx.Type() != COVERAGE {
+		t.Fatalf("Expected a coverage context")
+	}
+	if ctx.QueryParam("country") != "CN" {
+		t.Fatalf("Expected the country to be kept, got %q", ctx.QueryParam("country"))
+	}
+}
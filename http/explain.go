@@ -0,0 +1,139 @@
+// Copyright (c) 2014-2019 Ludovic Fauvet
+// Licensed under the MIT license
+
+package http
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/url"
+
+	. "github.com/opensourceways/mirrorbits/config"
+	"github.com/opensourceways/mirrorbits/filesystem"
+	"github.com/opensourceways/mirrorbits/mirrors"
+)
+
+// Explain returns the details of the selection of the mirrors
+// serving the given path to a client at the given address
+func (h *HTTP) Explain(ip, path string) (*mirrors.Explanation, error) {
+	r, err := http.NewRequest("GET", (&url.URL{Path: path}).String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	urlPath, err := sanitizePath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	h.templates.RLock()
+	ctx := NewContext(nil, r, h.templates)
+	h.templates.RUnlock()
+
+	return h.explain(ctx, ip, urlPath)
+}
+
+func (h *HTTP) explain(ctx *Context, ip, urlPath string) (*mirrors.Explanation, error) {
+	fileInfo := filesystem.NewFileInfo(urlPath)
+	clientInfo := locateUnlocated(h.geoip.GetRecord(ip), GetConfig())
+
+	mlist, excluded, err := h.mirrorSelector(ctx, h.cache, &fileInfo, clientInfo)
+	if _, ok := err.(net.Error); ok {
+		return nil, err
+	}
+
+	_, isDir := filesystem.GetRepoFiles(urlPath)
+
+	fallback := len(mlist) == 0
+	if fallback {
+		var down mirrors.Mirrors
+		mlist, down = mirrors.GetFallbacks(clientInfo)
+		if len(mlist) > 0 {
+			excluded = append(excluded, down...)
+		}
+		mlist = mirrors.SelectFallbacks(clientInfo)
+	}
+
+	explanation := &mirrors.Explanation{
+		IP:         ip,
+		ClientInfo: clientInfo,
+		FileInfo:   fileInfo,
+		Fallback:   fallback,
+		Mirrors:    make([]mirrors.ExplainedMirror, 0, len(mlist)+len(excluded)),
+	}
+	for i, m := range mlist {
+		explanation.Mirrors = append(explanation.Mirrors, explainMirror(m, i+1, &fileInfo, isDir))
+	}
+	for _, m := range excluded {
+		explanation.Mirrors = append(explanation.Mirrors, explainMirror(m, 0, &fileInfo, isDir))
+	}
+	return explanation, nil
+}
+
+func explainMirror(m mirrors.Mirror, rank int, fileInfo *filesystem.FileInfo, isDir bool) mirrors.ExplainedMirror {
+	e := mirrors.ExplainedMirror{
+		ID:            m.ID,
+		Name:          m.Name,
+		Rank:          rank,
+		Distance:      m.Distance,
+		CountryScore:  m.ComputedScore[0],
+		Score:         m.ComputedScore[1],
+		DistanceScore: m.ComputedScore[2],
+		Weight:        m.Weight,
+		Utilization:   m.Utilization,
+		NetworkMatch:  m.NetworkMatch,
+		ShareCapped:   m.ShareCapped,
+		ASNGroupMatch: m.ASNGroupMatch,
+		ExcludeReason: m.ExcludeReason,
+	}
+	switch {
+	case isDir:
+		e.FileMatch = "complete directory"
+	case m.FileInfo == nil:
+		e.FileMatch = "unknown"
+	case m.FileInfo.Size != fileInfo.Size:
+		e.FileMatch = "size mismatch"
+	default:
+		e.FileMatch = "match"
+	}
+	return e
+}
+
+func (h *HTTP) explainHandler(w http.ResponseWriter, r *http.Request, ctx *Context) {
+	urlPath, err := sanitizePath(r.URL.Path)
+	if err != nil {
+		if err == filesystem.ErrOutsideRepo {
+			http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+			return
+		}
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		return
+	}
+
+	remoteIP := clientIP(r)
+	if fromip := ctx.QueryParam("fromip"); net.ParseIP(fromip) != nil {
+		remoteIP = fromip
+	}
+
+	explanation, err := h.explain(ctx, remoteIP, urlPath)
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+		return
+	}
+
+	var output []byte
+	if ctx.IsPretty() {
+		output, err = json.MarshalIndent(explanation, "", "    ")
+	} else {
+		output, err = json.Marshal(explanation)
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "private, no-cache")
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Write(output)
+}
@@ -0,0 +1,54 @@
+// Copyright (c) 2014-2019 Ludovic Fauvet
+// Licensed under the MIT license
+
+package http
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/opensourceways/mirrorbits/filesystem"
+	"github.com/opensourceways/mirrorbits/mirrors"
+)
+
+func TestExplainMirror(t *testing.T) {
+	fileInfo := &filesystem.FileInfo{Path: "/openEuler-22.03-LTS/ISO/a.iso", Size: 1024}
+
+	m := mirrors.Mirror{
+		ID:            1,
+		Name:          "m1",
+		Distance:      120,
+		ComputedScore: [3]int{1, 10, 381},
+		NetworkMatch:  "10.0.0.0/8",
+		FileInfo:      &filesystem.FileInfo{Size: 1024},
+	}
+
+	e := explainMirror(m, 2, fileInfo, false)
+	if e.Rank != 2 || e.CountryScore != 1 || e.Score != 10 || e.DistanceScore != 381 || e.NetworkMatch != "10.0.0.0/8" {
+		t.Fatalf("Unexpected explanation: %+v", e)
+	}
+	if e.FileMatch != "match" {
+		t.Fatalf("Expected the file to match, got %s", e.FileMatch)
+	}
+
+	m.FileInfo = &filesystem.FileInfo{Size: 10}
+	m.ExcludeReason = "File size mismatch"
+	if e = explainMirror(m, 0, fileInfo, false); e.FileMatch != "size mismatch" || e.ExcludeReason != "File size mismatch" {
+		t.Fatalf("Unexpected explanation: %+v", e)
+	}
+
+	m.FileInfo = nil
+	if e = explainMirror(m, 0, fileInfo, false); e.FileMatch != "unknown" {
+		t.Fatalf("Expected an unknown file status, got %s", e.FileMatch)
+	}
+	if e = explainMirror(m, 0, fileInfo, true); e.FileMatch != "complete directory" {
+		t.Fatalf("Expected a complete directory, got %s", e.FileMatch)
+	}
+}
+
+func TestContext_Explain(t *testing.T) {
+	ctx := NewContext(httptest.NewRecorder(), httptest.NewRequest("GET", "/openEuler-22.03-LTS?explain=1", nil), Templates{})
+	if ctx.Type() != EXPLAIN || !ctx.IsExplain() {
+		t.Fatalf("Expected an explain request")
+	}
+}
@@ -15,6 +15,7 @@ type gzipResponseWriter struct {
 	io.Writer
 	http.ResponseWriter
 	typeGuessed bool
+	identity    bool
 }
 
 func (w *gzipResponseWriter) Write(b []byte) (int, error) {
@@ -30,13 +31,33 @@ func (w *gzipResponseWriter) Write(b []byte) (int, error) {
 // NewGzipHandler is an HTTP handler used to compress responses if supported by the client
 func NewGzipHandler(fn http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		if !GetConfig().Gzip || !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+		if !GetConfig().Gzip {
+			fn(w, r)
+			return
+		}
+		// The representation depends on the Accept-Encoding of the request
+		w.Header().Add("Vary", "Accept-Encoding")
+		if !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
 			fn(w, r)
 			return
 		}
 		w.Header().Set("Content-Encoding", "gzip")
 		gz, _ := gzip.NewWriterLevel(w, 1)
-		defer gz.Close()
-		fn(&gzipResponseWriter{Writer: gz, ResponseWriter: w}, r)
+		gw := &gzipResponseWriter{Writer: gz, ResponseWriter: w}
+		fn(gw, r)
+		if !gw.identity {
+			gz.Close()
+		}
+	}
+}
+
+// identityWriter returns a ResponseWriter sending the response uncompressed,
+// as required by the responses having a Content-Length or a Content-Range
+func identityWriter(w http.ResponseWriter) http.ResponseWriter {
+	if gw, ok := w.(*gzipResponseWriter); ok {
+		gw.Header().Del("Content-Encoding")
+		gw.identity = true
+		return gw.ResponseWriter
 	}
+	return w
 }
@@ -0,0 +1,33 @@
+// Copyright (c) 2014-2019 Ludovic Fauvet
+// Licensed under the MIT license
+
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/opensourceways/mirrorbits/config"
+)
+
+func TestNewGzipHandler_Vary(t *testing.T) {
+	SetConfiguration(&Configuration{Gzip: true})
+
+	handler := NewGzipHandler(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("mirrorbits"))
+	})
+
+	for _, encoding := range []string{"gzip", ""} {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("Accept-Encoding", encoding)
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+		if rec.Header().Get("Vary") != "Accept-Encoding" {
+			t.Fatalf("Expected Vary: Accept-Encoding with %q, got %q", encoding, rec.Header().Get("Vary"))
+		}
+		if (rec.Header().Get("Content-Encoding") == "gzip") != (encoding == "gzip") {
+			t.Fatalf("Unexpected Content-Encoding %q with %q", rec.Header().Get("Content-Encoding"), encoding)
+		}
+	}
+}
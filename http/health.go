@@ -0,0 +1,176 @@
+// Copyright (c) 2014-2019 Ludovic Fauvet
+// Licensed under the MIT license
+
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	. "github.com/opensourceways/mirrorbits/config"
+	"github.com/opensourceways/mirrorbits/core"
+	"github.com/opensourceways/mirrorbits/filesystem"
+	"github.com/opensourceways/mirrorbits/scan"
+)
+
+const (
+	// checkOK, checkDegraded and checkFailed are the states of the checks,
+	// only the failed checks making the server unready
+	checkOK       = "ok"
+	checkDegraded = "degraded"
+	checkFailed   = "failed"
+)
+
+// HealthCheck is the result of a readiness check
+type HealthCheck struct {
+	Status  string
+	Message string `json:",omitempty"`
+}
+
+// HealthReport is the body of the liveness and readiness endpoints
+type HealthReport struct {
+	Status string
+	Checks map[string]HealthCheck `json:",omitempty"`
+}
+
+// livezHandler answers as long as the process is able to serve requests
+func (h *HTTP) livezHandler(w http.ResponseWriter, r *http.Request) {
+	writeHealthReport(w, HealthReport{Status: checkOK})
+}
+
+// readyzHandler reports whether the server is able to redirect the
+// clients, answering 503 if any of the checks failed
+func (h *HTTP) readyzHandler(w http.ResponseWriter, r *http.Request) {
+	cnf := GetConfig()
+
+	city, asn := h.geoip.Loaded()
+	report := HealthReport{
+		Status: checkOK,
+		Checks: map[string]HealthCheck{
+			"server":     h.checkServer(),
+			"redis":      h.checkRedis(),
+			"geoip":      checkGeoIP(city, asn, len(cnf.Fallbacks) > 0),
+			"templates":  h.checkTemplates(),
+			"filetree":   checkFileTree(filesystem.GetRepoEntryCount()),
+			"sourcescan": checkSourceScan(scan.LastSourceScan(), sourceScanMaxAge(cnf), time.Now()),
+		},
+	}
+	for _, c := range report.Checks {
+		if c.Status == checkFailed {
+			report.Status = checkFailed
+			break
+		}
+	}
+	writeHealthReport(w, report)
+}
+
+func writeHealthReport(w http.ResponseWriter, report HealthReport) {
+	output, err := json.MarshalIndent(report, "", "    ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	if report.Status == checkFailed {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	w.Write(output)
+}
+
+// checkServer fails once the server is stopping so the clients are
+// directed to the other nodes while the connections are drained
+func (h *HTTP) checkServer() HealthCheck {
+	h.stoppedMutex.Lock()
+	defer h.stoppedMutex.Unlock()
+	if h.stopped {
+		return HealthCheck{Status: checkFailed, Message: "stopping"}
+	}
+	return HealthCheck{Status: checkOK}
+}
+
+// checkRedis checks the connection to redis and the format of the database
+func (h *HTTP) checkRedis() HealthCheck {
+	if h.redis == nil {
+		return HealthCheck{Status: checkFailed, Message: "not connected"}
+	}
+	if h.redis.Failure() {
+		return HealthCheck{Status: checkFailed, Message: "connection failure"}
+	}
+
+	conn := h.redis.Get()
+	defer conn.Close()
+
+	version, err := redis.Int(conn.Do("GET", core.DBVersionKey))
+	if err != nil {
+		return HealthCheck{Status: checkFailed, Message: err.Error()}
+	}
+	if version != core.DBVersion {
+		return HealthCheck{Status: checkFailed, Message: fmt.Sprintf("database format version %d, expected %d", version, core.DBVersion)}
+	}
+	return HealthCheck{Status: checkOK}
+}
+
+// checkGeoIP checks the GeoIP databases, the clients being served
+// by the fallbacks when the city database is missing
+func checkGeoIP(city, asn, fallbacks bool) HealthCheck {
+	switch {
+	case !city && fallbacks:
+		return HealthCheck{Status: checkDegraded, Message: "city database not loaded, serving the fallbacks"}
+	case !city:
+		return HealthCheck{Status: checkFailed, Message: "city database not loaded"}
+	case !asn:
+		return HealthCheck{Status: checkDegraded, Message: "ASN database not loaded"}
+	}
+	return HealthCheck{Status: checkOK}
+}
+
+// checkTemplates checks the templates, the previous ones being
+// kept when a reload fails
+func (h *HTTP) checkTemplates() HealthCheck {
+	if h.templates.RWMutex == nil {
+		return HealthCheck{Status: checkFailed, Message: "not loaded"}
+	}
+	h.templates.RLock()
+	defer h.templates.RUnlock()
+	if h.templates.mirrorlist == nil || h.templates.mirrorstats == nil || h.templates.coverage == nil {
+		return HealthCheck{Status: checkFailed, Message: "not loaded"}
+	}
+	if h.templates.err != nil {
+		return HealthCheck{Status: checkDegraded, Message: "reload failed: " + h.templates.err.Error()}
+	}
+	return HealthCheck{Status: checkOK}
+}
+
+// checkFileTree checks that the file tree of the repository is not empty
+func checkFileTree(entries int) HealthCheck {
+	if entries == 0 {
+		return HealthCheck{Status: checkFailed, Message: "empty file tree"}
+	}
+	return HealthCheck{Status: checkOK, Message: fmt.Sprintf("%d entries", entries)}
+}
+
+// checkSourceScan checks that the local repository was scanned
+// successfully within maxAge, if not zero
+func checkSourceScan(last time.Time, maxAge time.Duration, now time.Time) HealthCheck {
+	if last.IsZero() {
+		return HealthCheck{Status: checkFailed, Message: "never scanned"}
+	}
+	age := now.Sub(last).Round(time.Second)
+	if maxAge > 0 && age > maxAge {
+		return HealthCheck{Status: checkFailed, Message: fmt.Sprintf("last scanned %s ago, more than %s", age, maxAge)}
+	}
+	return HealthCheck{Status: checkOK, Message: fmt.Sprintf("last scanned %s ago", age)}
+}
+
+// sourceScanMaxAge returns the maximum age of the last scan of the local
+// repository, three scan intervals by default, zero if unlimited
+func sourceScanMaxAge(cnf *Configuration) time.Duration {
+	if cnf.SourceScanMaxAge > 0 {
+		return time.Duration(cnf.SourceScanMaxAge) * time.Minute
+	}
+	return 3 * time.Duration(cnf.RepositoryScanInterval) * time.Minute
+}
@@ -0,0 +1,95 @@
+// Copyright (c) 2014-2019 Ludovic Fauvet
+// Licensed under the MIT license
+
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/opensourceways/mirrorbits/config"
+	"github.com/opensourceways/mirrorbits/network"
+)
+
+func TestReadyzHandler(t *testing.T) {
+	SetConfiguration(&Configuration{RepositoryScanInterval: 60})
+
+	h := &HTTP{geoip: network.NewGeoIP()}
+
+	rec := httptest.NewRecorder()
+	h.livezHandler(rec, httptest.NewRequest("GET", "/livez", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected the liveness to succeed, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	h.readyzHandler(rec, httptest.NewRequest("GET", "/readyz", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("Expected the readiness to fail, got %d", rec.Code)
+	}
+
+	var report HealthReport
+	if err := json.Unmarshal(rec.Body.Bytes(), &report); err != nil {
+		t.Fatalf("Invalid report: %s", err)
+	}
+	if report.Status != checkFailed {
+		t.Fatalf("Expected a failed report, got %q", report.Status)
+	}
+	for _, name := range []string{"server", "redis", "geoip", "templates", "filetree", "sourcescan"} {
+		if _, ok := report.Checks[name]; !ok {
+			t.Fatalf("Missing the %s check in %+v", name, report.Checks)
+		}
+	}
+	if c := report.Checks["server"]; c.Status != checkOK {
+		t.Fatalf("Unexpected server check %+v", c)
+	}
+	if c := report.Checks["redis"]; c.Status != checkFailed {
+		t.Fatalf("Unexpected redis check %+v", c)
+	}
+}
+
+func TestHealthChecks(t *testing.T) {
+	if c := checkGeoIP(false, false, false); c.Status != checkFailed {
+		t.Fatalf("Expected the missing city database to fail, got %+v", c)
+	}
+	if c := checkGeoIP(false, true, true); c.Status != checkDegraded {
+		t.Fatalf("Expected the fallbacks to degrade the service, got %+v", c)
+	}
+	if c := checkGeoIP(true, false, false); c.Status != checkDegraded {
+		t.Fatalf("Expected the missing ASN database to degrade the service, got %+v", c)
+	}
+	if c := checkGeoIP(true, true, false); c.Status != checkOK {
+		t.Fatalf("Expected the GeoIP check to succeed, got %+v", c)
+	}
+
+	if c := checkFileTree(0); c.Status != checkFailed {
+		t.Fatalf("Expected the empty file tree to fail, got %+v", c)
+	}
+	if c := checkFileTree(10); c.Status != checkOK {
+		t.Fatalf("Expected the file tree check to succeed, got %+v", c)
+	}
+
+	now := time.Now()
+	if c := checkSourceScan(time.Time{}, time.Hour, now); c.Status != checkFailed {
+		t.Fatalf("Expected the missing scan to fail, got %+v", c)
+	}
+	if c := checkSourceScan(now.Add(-2*time.Hour), time.Hour, now); c.Status != checkFailed {
+		t.Fatalf("Expected the outdated scan to fail, got %+v", c)
+	}
+	if c := checkSourceScan(now.Add(-2*time.Hour), 0, now); c.Status != checkOK {
+		t.Fatalf("Expected the unlimited age to succeed, got %+v", c)
+	}
+	if c := checkSourceScan(now.Add(-time.Minute), time.Hour, now); c.Status != checkOK {
+		t.Fatalf("Expected the recent scan to succeed, got %+v", c)
+	}
+
+	if d := sourceScanMaxAge(&Configuration{RepositoryScanInterval: 60}); d != 3*time.Hour {
+		t.Fatalf("Expected three scan intervals, got %s", d)
+	}
+	if d := sourceScanMaxAge(&Configuration{RepositoryScanInterval: 60, SourceScanMaxAge: 30}); d != 30*time.Minute {
+		t.Fatalf("Expected the configured age, got %s", d)
+	}
+}
@@ -4,6 +4,7 @@
 package http
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -30,6 +31,7 @@ import (
 	"github.com/opensourceways/mirrorbits/mirrors"
 	"github.com/opensourceways/mirrorbits/network"
 	"github.com/opensourceways/mirrorbits/utils"
+	"github.com/prometheus/client_golang/prometheus"
 	"gopkg.in/tylerb/graceful.v1"
 )
 
@@ -48,6 +50,10 @@ type HTTP struct {
 	stats          *Stats
 	cache          *mirrors.Cache
 	engine         mirrorSelection
+	bandwidth      *bandwidthTracker
+	shares         *shareTracker
+	routing        *routingTable
+	coverage       coverageMemo
 	Restarting     bool
 	stopped        bool
 	stoppedMutex   sync.Mutex
@@ -59,6 +65,9 @@ type Templates struct {
 
 	mirrorlist  *template.Template
 	mirrorstats *template.Template
+	coverage    *template.Template
+	// err is the error of the last reload, the previous templates being kept
+	err error
 }
 
 // HTTPServer is the constructor of the HTTP server
@@ -69,14 +78,24 @@ func HTTPServer(redis *database.Redis, cache *mirrors.Cache) *HTTP {
 	h.templates.RWMutex = new(sync.RWMutex)
 	h.templates.mirrorlist = template.Must(h.LoadTemplates("mirrorlist"))
 	h.templates.mirrorstats = template.Must(h.LoadTemplates("mirrorstats"))
+	h.templates.coverage = template.Must(h.LoadTemplates("coverage"))
 	h.cache = cache
 	h.stats = NewStats(redis)
-	h.engine = DefaultEngine{}
+	h.bandwidth = newBandwidthTracker(redis)
+	h.shares = newShareTracker(redis)
+	h.routing = newRoutingTable(redis)
+	h.engine = DefaultEngine{bandwidth: h.bandwidth, shares: h.shares, routing: h.routing}
 	http.Handle("/", NewGzipHandler(h.requestDispatcher))
+	http.Handle("/metalink", NewGzipHandler(h.repoMetalinkHandler))
+	http.Handle("/mirrorlist", NewGzipHandler(h.repoMirrorlistHandler))
+	http.Handle(apiPrefix, NewGzipHandler(h.apiHandler))
 	http.HandleFunc("/healthz", func(writer http.ResponseWriter, request *http.Request) {
 		writer.WriteHeader(200)
 		writer.Write([]byte("ok"))
 	})
+	http.HandleFunc("/livez", h.livezHandler)
+	http.HandleFunc("/readyz", h.readyzHandler)
+	h.registerMetrics()
 
 	// Load the GeoIP databases
 	if err := h.geoip.LoadGeoIP(); err != nil {
@@ -128,6 +147,9 @@ func (h *HTTP) Terminate() {
 	}
 	/* Commit the latest recorded stats to the database */
 	h.stats.Terminate()
+	h.bandwidth.Stop()
+	h.shares.Stop()
+	h.routing.Stop()
 }
 
 // StopChan returns a channel that notifies when the server is stopped
@@ -142,15 +164,24 @@ func (h *HTTP) Reload() {
 
 	// Reload the templates
 	h.templates.Lock()
+	h.templates.err = nil
 	if t, err := h.LoadTemplates("mirrorlist"); err == nil {
 		h.templates.mirrorlist = t
 	} else {
 		log.Errorf("could not reload templates 'mirrorlist': %s", err.Error())
+		h.templates.err = err
 	}
 	if t, err := h.LoadTemplates("mirrorstats"); err == nil {
 		h.templates.mirrorstats = t
 	} else {
 		log.Errorf("could not reload templates 'mirrorstats': %s", err.Error())
+		h.templates.err = err
+	}
+	if t, err := h.LoadTemplates("coverage"); err == nil {
+		h.templates.coverage = t
+	} else {
+		log.Errorf("could not reload templates 'coverage': %s", err.Error())
+		h.templates.err = err
 	}
 	h.templates.Unlock()
 }
@@ -209,41 +240,65 @@ func (h *HTTP) requestDispatcher(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Server", "Mirrorbits/"+core.VERSION)
 
 	switch ctx.Type() {
-	case MIRRORLIST:
+	case MIRRORLIST, METALINK:
 		fallthrough
 	case STANDARD:
 		h.mirrorHandler(w, r, ctx)
 	case MIRRORSTATS:
 		h.mirrorStatsHandler(w, r, ctx)
+	case COVERAGE:
+		h.coverageHandler(w, r, ctx)
 	case FILESTATS:
 		h.fileStatsHandler(w, r, ctx)
 	case CHECKSUM:
 		h.checksumHandler(w, r, ctx)
+	case SHA256SUMS:
+		h.sha256sumsHandler(w, r, ctx)
+	case EXPLAIN:
+		h.explainHandler(w, r, ctx)
+	case TORRENT:
+		h.torrentHandler(w, r, ctx)
 	}
 }
 
-// select mirrors based on file or directory
+// select mirrors based on file or directory, a directory being served only by
+// the mirrors carrying every file it contains that is checked by the scans.
+// A file not checked by the scans is served by the mirrors of its closest
+// directory containing checked files, without any check of the file itself
+// beside excluding the mirrors indexed with another size of the file.
 func (h *HTTP) mirrorSelector(ctx *Context, cache *mirrors.Cache, fileInfo *filesystem.FileInfo,
 	clientInfo network.GeoIPRecord) (mirrors.Mirrors, mirrors.Mirrors, error) {
 
+	defer prometheus.NewTimer(selectionDuration.WithLabelValues()).ObserveDuration()
+
 	cnf := GetConfig()
 
-	relPath, err := filepath.Abs(cnf.Repository + fileInfo.Path)
-	if err != nil {
-		return nil, nil, err
+	// Prepare and return the list of all potential mirrors
+	files, dir, isDir := filesystem.GetIndexedFiles(fileInfo.Path)
+	if len(files) == 0 {
+		return nil, nil, nil
 	}
-	_, err = os.Stat(relPath)
-	if err != nil {
-		return nil, nil, err
+
+	var err error
+	if !isDir {
+		var f filesystem.FileInfo
+		f, err = cache.GetFileInfo(strings.Trim(fileInfo.Path, "/"))
+		if err != nil {
+			return nil, nil, err
+		}
+		f.Path = fileInfo.Path
+		*fileInfo = f
 	}
 
-	// Prepare and return the list of all potential mirrors
-	repoVersionList := filesystem.GetSelectorList()
-	if len(repoVersionList) == 0 || len(repoVersionList[fileInfo.Path[1:]]) == 0 {
-		return nil, nil, nil
+	var allMirrorList mirrors.Mirrors
+	if dir == "" {
+		allMirrorList, err = cache.GetMirrors(files[0], clientInfo)
+	} else {
+		allMirrorList, err = cache.GetDirMirrors(dir, files, clientInfo)
+		if err == nil && !isDir {
+			allMirrorList = dropSizeMismatches(cache, allMirrorList, fileInfo)
+		}
 	}
-	version := fileInfo.Path[1:]
-	allMirrorList, err := cache.GetMirrors(repoVersionList[version][0].Dir+filesystem.Sep+repoVersionList[version][0].Name, clientInfo)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -252,21 +307,45 @@ func (h *HTTP) mirrorSelector(ctx *Context, cache *mirrors.Cache, fileInfo *file
 		return nil, nil, errors.New("neither of mirrors have requested file(s)")
 	}
 
-	// since selected files are found in mirrors, we can use first file for detection
-	mList, mExcluded, err := h.engine.Selection(ctx, allMirrorList[0].FileInfo, clientInfo, allMirrorList, cnf)
+	mList, mExcluded, err := h.engine.Selection(ctx, fileInfo, clientInfo, allMirrorList, cnf)
 	if err != nil {
 		return nil, nil, err
 	}
 	return mList, mExcluded, nil
 }
 
+// dropSizeMismatches removes the mirrors whose index of the file disagrees
+// with the size of the file in the local repository
+func dropSizeMismatches(cache *mirrors.Cache, mlist mirrors.Mirrors, fileInfo *filesystem.FileInfo) mirrors.Mirrors {
+	if fileInfo.Size <= 0 {
+		return mlist
+	}
+	path := strings.Trim(fileInfo.Path, "/")
+	kept := make(mirrors.Mirrors, 0, len(mlist))
+	for _, m := range mlist {
+		if f, err := cache.GetFileInfoMirror(m.ID, path); err == nil && f.Size > 0 && f.Size != fileInfo.Size {
+			continue
+		}
+		kept = append(kept, m)
+	}
+	return kept
+}
+
 func (h *HTTP) mirrorHandler(w http.ResponseWriter, r *http.Request, ctx *Context) {
 	//XXX it would be safer to recover in case of panic
 
 	cnf := GetConfig()
 
 	var results *mirrors.Results
-	if len(r.URL.Path) <= 1 {
+	if len(ctx.Path()) <= 1 {
+		// The version list is cached unless answered by a redirection
+		if !redirects(r, ctx, cnf) && !ctx.IsMetalink() {
+			w.Header().Add("Vary", "Accept")
+			etag, modTime := sourceETag()
+			if notModified(w, r, etag, modTime, cnf.HTTPCache.Versions) {
+				return
+			}
+		}
 		results = &mirrors.Results{
 			RepoVersion: filesystem.GetRepoVersionList(),
 		}
@@ -275,14 +354,8 @@ func (h *HTTP) mirrorHandler(w http.ResponseWriter, r *http.Request, ctx *Contex
 		return
 	}
 
-	// TODO Compatible with openeuler online website api, temporary solution: edit url path
-	editedUrlPath := r.URL.Path
-	if strings.HasSuffix(editedUrlPath, "/ISO/") {
-		editedUrlPath = editedUrlPath[:len(editedUrlPath)-5]
-	}
-
 	// Sanitize path
-	urlPath, err := filesystem.EvaluateFilePath(cnf.Repository, editedUrlPath)
+	urlPath, err := sanitizePath(ctx.Path())
 	if err != nil {
 		if err == filesystem.ErrOutsideRepo {
 			http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
@@ -292,13 +365,25 @@ func (h *HTTP) mirrorHandler(w http.ResponseWriter, r *http.Request, ctx *Contex
 		return
 	}
 
-	fileInfo := filesystem.NewFileInfo(urlPath)
+	if ctx.IsMetalink() {
+		// A metalink describes a single file
+		if _, isDir := filesystem.GetRepoFiles(urlPath); isDir {
+			http.NotFound(w, r)
+			return
+		}
+	}
 
-	remoteIP := network.ExtractRemoteIP(r.Header.Get("X-Forwarded-For"))
-	if len(remoteIP) == 0 {
-		remoteIP = network.RemoteIPFromAddr(r.RemoteAddr)
+	// Small files are served by mirrorbits itself
+	if fi, ok := localFile(urlPath, cnf); ok && fi.Size() <= cnf.LocalServe.MaxSize && redirects(r, ctx, cnf) {
+		if h.serveLocal(w, r, urlPath, cnf) {
+			return
+		}
 	}
 
+	fileInfo := filesystem.NewFileInfo(urlPath)
+
+	remoteIP := clientIP(r)
+
 	if ctx.IsMirrorlist() {
 		fromip := ctx.QueryParam("fromip")
 		if net.ParseIP(fromip) != nil {
@@ -309,57 +394,45 @@ func (h *HTTP) mirrorHandler(w http.ResponseWriter, r *http.Request, ctx *Contex
 	clientInfo := h.geoip.GetRecord(remoteIP) //TODO return a pointer?
 	log.Infof("client %s request file %s", remoteIP, fileInfo.Path)
 
+	unlocated := !clientInfo.IsValid()
+	if unlocated {
+		h.stats.CountUnlocated(unlocatedPolicy(cnf))
+		clientInfo = locateUnlocated(clientInfo, cnf)
+	}
+
 	mlist, excluded, err := h.mirrorSelector(ctx, h.cache, &fileInfo, clientInfo)
 
 	/* Handle errors */
 	fallback := false
 	if _, ok := err.(net.Error); ok || len(mlist) == 0 {
-		log.Errorf("failed to get mirror info, error: %v and mirror size %d", err, len(mlist))
 		/* Handle fallbacks */
-		fallbacks := cnf.Fallbacks
-		if len(fallbacks) > 0 {
-			fallback = true
-			for i, f := range fallbacks {
-				country := ""
-				if strings.ToUpper(f.CountryCode) == "CN" || strings.ToUpper(f.CountryCode) == "CHINA" {
-					country = "China"
-				}
-				mlist = append(mlist, mirrors.Mirror{
-					ID:               i * -1,
-					Name:             f.Name,
-					HttpURL:          f.URL,
-					CountryCodes:     strings.ToUpper(f.CountryCode),
-					Country:          country,
-					CountryFields:    []string{strings.ToUpper(f.CountryCode)},
-					NetworkBandwidth: f.NetworkBandwidth,
-					ContinentCode:    strings.ToUpper(f.ContinentCode)})
+		reason := "no mirror available"
+		if err != nil {
+			reason = err.Error()
+		} else if unlocated {
+			reason = "unlocated client"
+		}
+		if _, ok := localFile(urlPath, cnf); ok && redirects(r, ctx, cnf) {
+			log.Noticef("Serving %s locally: %s", urlPath, reason)
+			if h.serveLocal(w, r, urlPath, cnf) {
+				return
 			}
-			sort.Sort(mirrors.ByRank{Mirrors: mlist, ClientInfo: clientInfo})
-		} else {
+		}
+		mlist = mirrors.SelectFallbacks(clientInfo)
+		if len(mlist) == 0 {
 			// No fallback in stock, there's nothing else we can do
+			log.Errorf("Unable to serve %s: %s and no fallback available", fileInfo.Path, reason)
 			http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
 			return
 		}
+		fallback = true
+		fallbacksTotal.WithLabelValues(mlist[0].Name).Inc()
+		log.Noticef("Serving %s from the fallback %s: %s", fileInfo.Path, mlist[0].Name, reason)
 	} else if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	for i := range mlist {
-		if mlist[i].CountryCodes == "TWN" || mlist[i].CountryCodes == "TPE" || mlist[i].CountryCodes == "TW" {
-			mlist[i].CountryCodes = "CN"
-			mlist[i].Country = "China"
-		} else if mlist[i].CountryCodes == "HK" ||
-			mlist[i].CountryCodes == "HKSAR" || mlist[i].CountryCodes == "HKG" {
-			mlist[i].CountryCodes = "CN"
-			mlist[i].Country = "China"
-		} else if mlist[i].CountryCodes == "MO" ||
-			mlist[i].CountryCodes == "MC" || mlist[i].CountryCodes == "OMA" {
-			mlist[i].CountryCodes = "CN"
-			mlist[i].Country = "China"
-		}
-	}
-
 	limit := len(mlist)
 	if limit > 5 {
 		limit = 5
@@ -378,20 +451,42 @@ func (h *HTTP) mirrorHandler(w http.ResponseWriter, r *http.Request, ctx *Contex
 
 	handlerRes(w, r, ctx, results, cnf)
 
-	if !ctx.IsMirrorlist() {
-		if len(mlist) > 0 {
-			h.stats.CountDownload(mlist[0], fileInfo)
+	if !ctx.IsMirrorlist() && !ctx.IsMetalink() {
+		if fallback {
+			h.stats.CountFallback(mlist[0], fileInfo)
+		} else if len(mlist) > 0 {
+			h.stats.CountDownload(mlist[0], fileInfo, clientInfo)
 		}
 	}
 
 	return
 }
 
+// sanitizePath returns the path of the requested file within the repository
+func sanitizePath(path string) (string, error) {
+	// TODO Compatible with openeuler online website api, temporary solution: edit url path
+	if strings.HasSuffix(path, "/ISO/") {
+		path = path[:len(path)-5]
+	}
+	return filesystem.EvaluateFilePath(GetConfig().Repository, path)
+}
+
+// clientIP returns the address of the client, taking the proxies into account
+func clientIP(r *http.Request) string {
+	remoteIP := network.ExtractRemoteIP(r.Header.Get("X-Forwarded-For"))
+	if len(remoteIP) == 0 {
+		remoteIP = network.RemoteIPFromAddr(r.RemoteAddr)
+	}
+	return remoteIP
+}
+
 func handlerRes(w http.ResponseWriter, r *http.Request, ctx *Context, results *mirrors.Results, cnf *Configuration) {
 	var resultRenderer resultsRenderer
 
 	if ctx.IsMirrorlist() {
 		resultRenderer = &MirrorListRenderer{}
+	} else if ctx.IsMetalink() {
+		resultRenderer = &MetalinkRenderer{}
 	} else {
 		switch cnf.OutputMode {
 		case "json":
@@ -411,11 +506,16 @@ func handlerRes(w http.ResponseWriter, r *http.Request, ctx *Context, results *m
 		}
 	}
 
-	w.Header().Set("Cache-Control", "private, no-cache")
+	if w.Header().Get("Cache-Control") == "" {
+		w.Header().Set("Cache-Control", "private, no-cache")
+	}
 
 	status, err := resultRenderer.Write(ctx, results)
 	if err != nil {
+		uncacheable(w)
 		http.Error(w, err.Error(), status)
+	} else if len(results.MirrorList) > 0 {
+		redirectsTotal.WithLabelValues(results.MirrorList[0].Name, results.ClientInfo.CountryCode, strings.ToLower(resultRenderer.Type())).Inc()
 	}
 
 	if !ctx.IsMirrorlist() {
@@ -567,6 +667,59 @@ func (h *HTTP) checksumHandler(w http.ResponseWriter, r *http.Request, ctx *Cont
 	return
 }
 
+// sha256sumsHandler returns a SHA256SUMS file listing the hashes of all the
+// files of a directory, their paths being relative to the directory
+func (h *HTTP) sha256sumsHandler(w http.ResponseWriter, r *http.Request, ctx *Context) {
+
+	// Sanitize path
+	urlPath, err := sanitizePath(r.URL.Path)
+	if err != nil {
+		if err == filesystem.ErrOutsideRepo {
+			http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+			return
+		}
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		return
+	}
+
+	files, isDir := filesystem.GetRepoFiles(urlPath)
+	if len(files) == 0 {
+		http.NotFound(w, r)
+		return
+	}
+	sort.Strings(files)
+
+	dir := strings.Trim(urlPath, filesystem.Sep) + filesystem.Sep
+	if !isDir {
+		dir = filepath.Dir(files[0]) + filesystem.Sep
+	}
+
+	var buf bytes.Buffer
+	for _, file := range files {
+		fileInfo, err := h.cache.GetFileInfo(file)
+		if err != nil {
+			log.Errorf("Error while fetching Fileinfo: %s", err.Error())
+			http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+			return
+		}
+		if fileInfo.Sha256 == "" {
+			// Not hashed yet
+			continue
+		}
+		fmt.Fprintf(&buf, "%s  %s\n", fileInfo.Sha256, strings.TrimPrefix(file, dir))
+	}
+
+	w.Header().Set("Cache-Control", "private, no-cache")
+	if buf.Len() == 0 {
+		http.Error(w, "No SHA256 checksum available", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=UTF-8")
+	w.Header().Set("Content-Disposition", `inline; filename="SHA256SUMS"`)
+	buf.WriteTo(w)
+}
+
 // MirrorStats contains the stats of a given mirror
 type MirrorStats struct {
 	ID         int
@@ -577,6 +730,10 @@ type MirrorStats struct {
 	PercentB   float32
 	SyncOffset SyncOffset
 	TZOffset   time.Duration
+	// Versions is the number of versions known to be available on the mirror
+	Versions        int
+	MissingVersions []string
+	Shares          []mirrors.ShareUsage
 }
 
 type MirrorStatsExtended struct {
@@ -587,6 +744,8 @@ type MirrorStatsExtended struct {
 	PercentB   float32
 	SyncOffset SyncOffset
 	TZOffset   time.Duration
+	Rate       int64 // estimated bytes per second over the bandwidth window
+	Shares     []mirrors.ShareUsage
 }
 
 // SyncOffset contains the time offset between the mirror and the local repository
@@ -662,11 +821,24 @@ func (h *HTTP) mirrorStatsHandler(w http.ResponseWriter, r *http.Request, ctx *C
 	var results []MirrorStats
 	var jsonResults []MirrorStatsExtended
 	var index int64
+	version := ctx.QueryParam("version")
+	matches := h.routing.Match(net.ParseIP(clientIP(r)))
 	mlist := make([]mirrors.Mirror, 0, len(mirrorsIDs))
 	for _, id := range mirrorsIDs {
 		mirror, err := h.cache.GetMirror(id)
 		if err != nil {
 			log.Errorf("mirror [%d] occurred %s", id, err.Error())
+			index += 2
+			continue
+		}
+		if version != "" && !mirror.HasVersion(version) {
+			// Only list the mirrors serving the requested version
+			index += 2
+			continue
+		}
+		if _, ok := matches[id]; mirror.Private && !ok {
+			// Private mirrors are only listed to their networks
+			index += 2
 			continue
 		}
 		// test
@@ -697,6 +869,8 @@ func (h *HTTP) mirrorStatsHandler(w http.ResponseWriter, r *http.Request, ctx *C
 
 		elapsed := time.Since(lastModTime)
 
+		mirror.Utilization = h.bandwidth.Utilization(&mirror)
+
 		tzoffset, _ := time.ParseDuration(fmt.Sprintf("%dms", mirror.TZOffset))
 		if tzoffset != 0 {
 			hasTZAdjustement = true
@@ -712,20 +886,11 @@ func (h *HTTP) mirrorStatsHandler(w http.ResponseWriter, r *http.Request, ctx *C
 				Value:         int(elapsed.Hours()),
 				HumanReadable: utils.FuzzyTimeStr(elapsed),
 			},
-			TZOffset: tzoffset,
-		}
-		if mirror.CountryCodes == "TWN" || mirror.CountryCodes == "TPE" || mirror.CountryCodes == "TW" {
-			mirror.CountryCodes = "CN"
-			mirror.Country = "China"
-		} else if mirror.CountryCodes == "HK" ||
-			mirror.CountryCodes == "HKSAR" || mirror.CountryCodes == "HKG" {
-			mirror.CountryCodes = "CN"
-			mirror.Country = "China"
-		} else if mirror.CountryCodes == "MO" ||
-			mirror.CountryCodes == "MC" || mirror.CountryCodes == "OMA" {
-			mirror.CountryCodes = "CN"
-			mirror.Country = "China"
+			TZOffset:        tzoffset,
+			MissingVersions: mirror.Versions.Missing(),
+			Shares:          h.shares.Usage(&mirror),
 		}
+		s.Versions = len(mirror.Versions) - len(s.MissingVersions)
 		// construct json results
 		js := MirrorStatsExtended{
 			mirror,
@@ -734,7 +899,9 @@ func (h *HTTP) mirrorStatsHandler(w http.ResponseWriter, r *http.Request, ctx *C
 			s.PercentD,
 			s.PercentB,
 			s.SyncOffset,
-			s.TZOffset}
+			s.TZOffset,
+			h.bandwidth.Rate(id),
+			s.Shares}
 		results = append(results, s)
 		jsonResults = append(jsonResults, js)
 		index += 2
@@ -0,0 +1,39 @@
+// Copyright (c) 2014-2019 Ludovic Fauvet
+// Licensed under the MIT license
+
+package http
+
+import (
+	"testing"
+
+	"github.com/opensourceways/mirrorbits/filesystem"
+	"github.com/opensourceways/mirrorbits/mirrors"
+	. "github.com/opensourceways/mirrorbits/testing"
+)
+
+func TestDropSizeMismatches(t *testing.T) {
+	m, r := PrepareMiniredisTest(t)
+	r.ConnectPubsub()
+	cache := mirrors.NewCache(r)
+
+	path := "openEuler-24.03-LTS/everything/x86_64/Packages/a.rpm"
+	m.HSet("FILEINFO_2_"+path, "size", "2048")
+	m.HSet("FILEINFO_3_"+path, "size", "1024")
+
+	mlist := mirrors.Mirrors{{ID: 1, Name: "m1"}, {ID: 2, Name: "m2"}, {ID: 3, Name: "m3"}}
+	fileInfo := &filesystem.FileInfo{Path: "/" + path, Size: 1024}
+
+	kept := dropSizeMismatches(cache, mlist, fileInfo)
+	if len(kept) != 2 || kept[0].ID != 1 || kept[1].ID != 3 {
+		t.Fatalf("Expected the mirrors not indexed or indexed with the same size, got %v", kept)
+	}
+	if len(mlist) != 3 || mlist[1].ID != 2 {
+		t.Fatalf("The list of the mirrors must not be modified")
+	}
+
+	// Nothing to compare to
+	fileInfo.Size = 0
+	if kept := dropSizeMismatches(cache, mlist, fileInfo); len(kept) != 3 {
+		t.Fatalf("Expected all the mirrors, got %d", len(kept))
+	}
+}
@@ -0,0 +1,146 @@
+// Copyright (c) 2014-2019 Ludovic Fauvet
+// Licensed under the MIT license
+
+package http
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	. "github.com/opensourceways/mirrorbits/config"
+)
+
+const (
+	// localChunk is the size of the writes of the local transfers
+	localChunk = 32 << 10
+	// localBurst is the delay the local transfers may be ahead of the rate limit
+	localBurst = 100 * time.Millisecond
+)
+
+// localLimiter is shared by all the local transfers
+var localLimiter rateLimiter
+
+// rateLimiter limits the rate of the local transfers
+type rateLimiter struct {
+	sync.Mutex
+	next time.Time // time at which the bytes already sent are within the rate
+}
+
+// wait blocks until n more bytes can be sent at the given rate in bytes per second
+func (l *rateLimiter) wait(n int, rate int64) {
+	l.Lock()
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	l.next = l.next.Add(time.Duration(int64(n) * int64(time.Second) / rate))
+	delay := l.next.Sub(now) - localBurst
+	l.Unlock()
+
+	if delay > 0 {
+		time.Sleep(delay)
+	}
+}
+
+// localResponseWriter counts and limits the bytes of a local transfer
+type localResponseWriter struct {
+	http.ResponseWriter
+	rate   int64 // in bytes per second, 0 for unlimited
+	sent   int64
+	status int
+}
+
+func (w *localResponseWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *localResponseWriter) Write(b []byte) (int, error) {
+	var total int
+	for len(b) > 0 {
+		chunk := b
+		if len(chunk) > localChunk {
+			chunk = chunk[:localChunk]
+		}
+		if w.rate > 0 {
+			localLimiter.wait(len(chunk), w.rate)
+		}
+		n, err := w.ResponseWriter.Write(chunk)
+		total += n
+		w.sent += int64(n)
+		if err != nil {
+			return total, err
+		}
+		b = b[n:]
+	}
+	return total, nil
+}
+
+// redirects returns true if the request is answered by a redirection
+// to a mirror, the other answers not being replaceable by the file
+func redirects(r *http.Request, ctx *Context, cnf *Configuration) bool {
+	if ctx.IsMirrorlist() || ctx.IsMetalink() {
+		return false
+	}
+	switch cnf.OutputMode {
+	case "redirect":
+		return true
+	case "auto":
+		return !strings.Contains(r.Header.Get("Accept"), "application/json")
+	}
+	return false
+}
+
+// localFile returns the information of a regular file of the local repository
+func localFile(urlPath string, cnf *Configuration) (os.FileInfo, bool) {
+	if !cnf.LocalServe.Enabled {
+		return nil, false
+	}
+	fi, err := os.Stat(filepath.Join(cnf.Repository, urlPath))
+	if err != nil || !fi.Mode().IsRegular() {
+		return nil, false
+	}
+	return fi, true
+}
+
+// serveLocal sends the file from the local repository, supporting the range,
+// HEAD and conditional requests. It returns false if the file can't be opened.
+func (h *HTTP) serveLocal(w http.ResponseWriter, r *http.Request, urlPath string, cnf *Configuration) bool {
+	f, err := os.Open(filepath.Join(cnf.Repository, urlPath))
+	if err != nil {
+		log.Errorf("Unable to serve %s locally: %s", urlPath, err)
+		return false
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		log.Errorf("Unable to serve %s locally: %s", urlPath, err)
+		return false
+	}
+
+	// The length and the ranges of the response apply to the file itself
+	w = identityWriter(w)
+
+	// The transfer may outlast the write timeout of the server
+	if err := http.NewResponseController(w).SetWriteDeadline(time.Time{}); err != nil {
+		log.Debugf("Unable to lift the write deadline of %s: %s", urlPath, err)
+	}
+
+	lw := &localResponseWriter{
+		ResponseWriter: w,
+		rate:           int64(cnf.LocalServe.Bandwidth) * 1024,
+		status:         http.StatusOK,
+	}
+	http.ServeContent(lw, r, fi.Name(), fi.ModTime(), f)
+
+	// Only the transfers of the file are counted
+	if r.Method != http.MethodHead && (lw.status == http.StatusOK || lw.status == http.StatusPartialContent) {
+		h.stats.CountLocal(urlPath, lw.sent)
+	}
+	return true
+}
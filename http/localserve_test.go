@@ -0,0 +1,102 @@
+// Copyright (c) 2014-2019 Ludovic Fauvet
+// Licensed under the MIT license
+
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/opensourceways/mirrorbits/config"
+)
+
+func TestServeLocal(t *testing.T) {
+	repo := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(repo, "a"), 0755); err != nil {
+		t.Fatalf("Unable to create the repository: %s", err)
+	}
+	if err := os.WriteFile(filepath.Join(repo, "a", "b.iso.sha256sum"), []byte("0123456789"), 0644); err != nil {
+		t.Fatalf("Unable to write the file: %s", err)
+	}
+	cnf := &Configuration{
+		Repository: repo,
+		OutputMode: "redirect",
+		LocalServe: LocalServe{Enabled: true, MaxSize: 64},
+	}
+	SetConfiguration(cnf)
+
+	if _, ok := localFile("/a", cnf); ok {
+		t.Fatalf("Directories must not be served locally")
+	}
+	fi, ok := localFile("/a/b.iso.sha256sum", cnf)
+	if !ok || fi.Size() != 10 {
+		t.Fatalf("Expected the local file")
+	}
+
+	h := &HTTP{stats: &Stats{countChan: make(chan countItem, 10)}}
+
+	// Range requests
+	r := httptest.NewRequest("GET", "/a/b.iso.sha256sum", nil)
+	r.Header.Set("Range", "bytes=2-5")
+	rec := httptest.NewRecorder()
+	if !h.serveLocal(rec, r, "/a/b.iso.sha256sum", cnf) {
+		t.Fatalf("Expected the file to be served")
+	}
+	if rec.Code != http.StatusPartialContent || rec.Body.String() != "2345" {
+		t.Fatalf("Unexpected response %d %q", rec.Code, rec.Body.String())
+	}
+	if c := <-h.stats.countChan; !c.local || c.filepath != "/a/b.iso.sha256sum" || c.size != 4 {
+		t.Fatalf("Unexpected accounting: %+v", c)
+	}
+
+	// Conditional requests
+	r = httptest.NewRequest("GET", "/a/b.iso.sha256sum", nil)
+	r.Header.Set("If-Modified-Since", fi.ModTime().Add(time.Hour).UTC().Format(http.TimeFormat))
+	rec = httptest.NewRecorder()
+	h.serveLocal(rec, r, "/a/b.iso.sha256sum", cnf)
+	if rec.Code != http.StatusNotModified {
+		t.Fatalf("Expected %d, got %d", http.StatusNotModified, rec.Code)
+	}
+
+	// Neither the unmodified files nor the HEAD requests are counted
+	rec = httptest.NewRecorder()
+	h.serveLocal(rec, httptest.NewRequest("HEAD", "/a/b.iso.sha256sum", nil), "/a/b.iso.sha256sum", cnf)
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Length") != "10" || len(h.stats.countChan) != 0 {
+		t.Fatalf("Unexpected HEAD response %d", rec.Code)
+	}
+
+	if h.serveLocal(httptest.NewRecorder(), r, "/a/missing", cnf) {
+		t.Fatalf("Expected a missing file not to be served")
+	}
+}
+
+func TestRedirects(t *testing.T) {
+	cnf := &Configuration{OutputMode: "auto"}
+	r := httptest.NewRequest("GET", "/a/b.iso", nil)
+	if !redirects(r, NewContext(httptest.NewRecorder(), r, Templates{}), cnf) {
+		t.Fatalf("Expected a redirection")
+	}
+	r.Header.Set("Accept", "application/json")
+	if redirects(r, NewContext(httptest.NewRecorder(), r, Templates{}), cnf) {
+		t.Fatalf("Expected a JSON answer")
+	}
+	r = httptest.NewRequest("GET", "/a/b.iso?mirrorlist", nil)
+	if redirects(r, NewContext(httptest.NewRecorder(), r, Templates{}), &Configuration{OutputMode: "redirect"}) {
+		t.Fatalf("Expected the mirrorlist")
+	}
+}
+
+func TestRateLimiter(t *testing.T) {
+	var l rateLimiter
+	start := time.Now()
+	// 1000 B/s, the first 100ms being a burst
+	l.wait(100, 1000)
+	l.wait(200, 1000)
+	if elapsed := time.Since(start); elapsed < 150*time.Millisecond {
+		t.Fatalf("Expected the transfer to be limited, took %s", elapsed)
+	}
+}
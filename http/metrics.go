@@ -0,0 +1,107 @@
+// Copyright (c) 2014-2019 Ludovic Fauvet
+// Licensed under the MIT license
+
+package http
+
+import (
+	"net/http"
+	"time"
+
+	. "github.com/opensourceways/mirrorbits/config"
+	"github.com/opensourceways/mirrorbits/metrics"
+)
+
+const (
+	// metricsPath is the path of the metrics endpoint
+	metricsPath = "/metrics"
+)
+
+var (
+	redirectsTotal = metrics.NewCounter("mirrorbits_redirects_total",
+		"Requests answered with a mirror by mirror, client country and renderer.",
+		"mirror", "country", "renderer")
+	fallbacksTotal = metrics.NewCounter("mirrorbits_fallbacks_total",
+		"Requests answered with a fallback mirror by fallback.",
+		"mirror")
+	selectionDuration = metrics.NewHistogram("mirrorbits_selection_duration_seconds",
+		"Latency of the selection of the mirrors for a request.",
+		metrics.DefaultBuckets)
+)
+
+// registerMetrics registers the collectors reading the state of the
+// server at scrape time and exposes the metrics if enabled. The collectors
+// of another server registered before are kept.
+func (h *HTTP) registerMetrics() {
+	register := func(err error) {
+		if err != nil {
+			log.Warningf("Metrics: %s", err)
+		}
+	}
+	if h.cache != nil {
+		labels := []string{"cache"}
+		register(metrics.NewCounterFunc("mirrorbits_cache_hits_total", "Lookups of the local cache finding the key.", labels,
+			func(emit metrics.Emit) {
+				for _, s := range h.cache.Stats() {
+					emit(float64(s.Hits), s.Name)
+				}
+			}))
+		register(metrics.NewCounterFunc("mirrorbits_cache_misses_total", "Lookups of the local cache missing the key.", labels,
+			func(emit metrics.Emit) {
+				for _, s := range h.cache.Stats() {
+					emit(float64(s.Misses), s.Name)
+				}
+			}))
+		register(metrics.NewGaugeFunc("mirrorbits_cache_entries", "Entries of the local cache.", labels,
+			func(emit metrics.Emit) {
+				for _, s := range h.cache.Stats() {
+					emit(float64(s.Length), s.Name)
+				}
+			}))
+		register(metrics.NewGaugeFunc("mirrorbits_cache_size", "Approximate size of the local cache.", labels,
+			func(emit metrics.Emit) {
+				for _, s := range h.cache.Stats() {
+					emit(float64(s.Size), s.Name)
+				}
+			}))
+		register(metrics.NewGaugeFunc("mirrorbits_cache_capacity", "Capacity of the local cache.", labels,
+			func(emit metrics.Emit) {
+				for _, s := range h.cache.Stats() {
+					emit(float64(s.Capacity), s.Name)
+				}
+			}))
+	}
+	if h.redis != nil {
+		register(metrics.NewGaugeFunc("mirrorbits_redis_failure", "Whether the connection to redis is failing.", nil,
+			func(emit metrics.Emit) {
+				failure := 0.0
+				if h.redis.Failure() {
+					failure = 1
+				}
+				emit(failure)
+			}))
+	}
+
+	cnf := GetConfig()
+	if !cnf.Metrics.Enabled {
+		return
+	}
+	if cnf.Metrics.ListenAddress == "" {
+		http.Handle(metricsPath, metrics.Handler())
+		return
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(metricsPath, metrics.Handler())
+	server := &http.Server{
+		Addr:         cnf.Metrics.ListenAddress,
+		Handler:      mux,
+		ReadTimeout:  10 * time.Second,
+		WriteTimeout: 10 * time.Second,
+	}
+	go func() {
+		log.Infof("Metrics listening on %s", cnf.Metrics.ListenAddress)
+		if err := server.ListenAndServe(); err != nil {
+			log.Errorf("Metrics: %s", err)
+		}
+	}()
+}
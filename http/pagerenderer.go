@@ -6,14 +6,17 @@ package http
 import (
 	"bytes"
 	"encoding/json"
+	"encoding/xml"
 	"errors"
 	"fmt"
 	"net/http"
+	"path/filepath"
 	"sort"
 	"strconv"
 	"strings"
 
 	. "github.com/opensourceways/mirrorbits/config"
+	"github.com/opensourceways/mirrorbits/core"
 	"github.com/opensourceways/mirrorbits/mirrors"
 )
 
@@ -133,3 +136,102 @@ func (w *MirrorListRenderer) Write(ctx *Context, results *mirrors.Results) (stat
 	buf.WriteTo(ctx.ResponseWriter())
 	return http.StatusOK, nil
 }
+
+// MetalinkRenderer is used to render the mirrors serving the file as a Metalink (RFC 5854) document
+type MetalinkRenderer struct{}
+
+type metalink struct {
+	XMLName   xml.Name       `xml:"urn:ietf:params:xml:ns:metalink metalink"`
+	Generator string         `xml:"generator"`
+	Files     []metalinkFile `xml:"file"`
+}
+
+type metalinkFile struct {
+	Name   string         `xml:"name,attr"`
+	Size   int64          `xml:"size,omitempty"`
+	Hashes []metalinkHash `xml:"hash"`
+	URLs   []metalinkURL  `xml:"url"`
+}
+
+type metalinkHash struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+type metalinkURL struct {
+	Location string `xml:"location,attr,omitempty"`
+	Priority int    `xml:"priority,attr"`
+	URL      string `xml:",chardata"`
+}
+
+// Type returns the type of renderer
+func (w *MetalinkRenderer) Type() string {
+	return "METALINK"
+}
+
+// Write is used to write the result to the ResponseWriter
+func (w *MetalinkRenderer) Write(ctx *Context, results *mirrors.Results) (statusCode int, err error) {
+	if len(results.MirrorList) == 0 {
+		// No mirror returned for this request
+		http.NotFound(ctx.ResponseWriter(), ctx.Request())
+		return http.StatusNotFound, nil
+	}
+
+	path := strings.TrimPrefix(results.FileInfo.Path, "/")
+	name := filepath.Base(results.FileInfo.Path)
+
+	file := metalinkFile{
+		Name: name,
+		Size: results.FileInfo.Size,
+	}
+
+	// Hash names as registered by the IANA, strongest first
+	sha256 := results.FileInfo.Sha256
+	if sha256 == "" {
+		sha256 = results.FileInfo.Sha256FileValue
+	}
+	for _, h := range []metalinkHash{
+		{Type: "sha-256", Value: sha256},
+		{Type: "sha-1", Value: results.FileInfo.Sha1},
+		{Type: "md5", Value: results.FileInfo.Md5},
+	} {
+		if h.Value != "" {
+			file.Hashes = append(file.Hashes, h)
+		}
+	}
+
+	// The mirrors are already ranked, the lowest priority being the preferred one
+	for i, m := range results.MirrorList {
+		var countryCode string
+		if len(m.CountryFields) > 0 {
+			countryCode = strings.ToLower(m.CountryFields[0])
+		}
+		file.URLs = append(file.URLs, metalinkURL{
+			Location: countryCode,
+			Priority: i + 1,
+			URL:      m.HttpURL + path,
+		})
+	}
+
+	doc := metalink{
+		Generator: "Mirrorbits/" + core.VERSION,
+		Files:     []metalinkFile{file},
+	}
+
+	var output []byte
+	if ctx.IsPretty() {
+		output, err = xml.MarshalIndent(doc, "", "    ")
+	} else {
+		output, err = xml.Marshal(doc)
+	}
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	output = append([]byte(xml.Header), output...)
+
+	ctx.ResponseWriter().Header().Set("Content-Type", "application/metalink4+xml")
+	ctx.ResponseWriter().Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name+".meta4"))
+	ctx.ResponseWriter().Header().Set("Content-Length", strconv.Itoa(len(output)))
+	ctx.ResponseWriter().Write(output)
+	return http.StatusOK, nil
+}
@@ -0,0 +1,91 @@
+// Copyright (c) 2014-2019 Ludovic Fauvet
+// Licensed under the MIT license
+
+package http
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/opensourceways/mirrorbits/config"
+	"github.com/opensourceways/mirrorbits/filesystem"
+	"github.com/opensourceways/mirrorbits/mirrors"
+)
+
+func TestContext_Metalink(t *testing.T) {
+	for _, target := range []string{"/a/b.iso?metalink", "/a/b.iso.meta4", "/a/b.iso.metalink"} {
+		ctx := NewContext(httptest.NewRecorder(), httptest.NewRequest("GET", target, nil), Templates{})
+		if ctx.Type() != METALINK || !ctx.IsMetalink() {
+			t.Fatalf("Expected a metalink request for %s", target)
+		}
+		if ctx.Path() != "/a/b.iso" {
+			t.Fatalf("Expected /a/b.iso, got %s", ctx.Path())
+		}
+	}
+
+	r := httptest.NewRequest("GET", "/a/b.iso", nil)
+	r.Header.Set("Accept", "application/metalink4+xml, */*")
+	if ctx := NewContext(httptest.NewRecorder(), r, Templates{}); !ctx.IsMetalink() {
+		t.Fatalf("Expected a metalink request")
+	}
+
+	ctx := NewContext(httptest.NewRecorder(), httptest.NewRequest("GET", "/a/b.iso", nil), Templates{})
+	if ctx.IsMetalink() || ctx.Path() != "/a/b.iso" {
+		t.Fatalf("Expected a standard request")
+	}
+}
+
+func TestMetalinkRenderer_Write(t *testing.T) {
+	SetConfiguration(&Configuration{})
+
+	rec := httptest.NewRecorder()
+	ctx := NewContext(rec, httptest.NewRequest("GET", "/a/b.iso.meta4", nil), Templates{})
+
+	results := &mirrors.Results{
+		FileInfo: filesystem.FileInfo{Path: "/a/b.iso", Size: 1024, Sha256: "abcd", Md5: "ef01"},
+		MirrorList: mirrors.Mirrors{
+			{ID: 1, HttpURL: "http://m1.example.org/", CountryFields: []string{"CN"}},
+			{ID: 2, HttpURL: "http://m2.example.org/repo/", CountryFields: []string{"FR"}},
+		},
+	}
+
+	status, err := (&MetalinkRenderer{}).Write(ctx, results)
+	if err != nil || status != http.StatusOK {
+		t.Fatalf("Unexpected result: %d %v", status, err)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/metalink4+xml" {
+		t.Fatalf("Unexpected content type %s", ct)
+	}
+
+	var doc metalink
+	if err := xml.Unmarshal(rec.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("Unable to parse the metalink: %s", err)
+	}
+	if doc.XMLName.Space != "urn:ietf:params:xml:ns:metalink" || len(doc.Files) != 1 {
+		t.Fatalf("Unexpected metalink: %+v", doc)
+	}
+	f := doc.Files[0]
+	if f.Name != "b.iso" || f.Size != 1024 {
+		t.Fatalf("Unexpected file: %+v", f)
+	}
+	if len(f.Hashes) != 2 || f.Hashes[0].Type != "sha-256" || f.Hashes[0].Value != "abcd" || f.Hashes[1].Type != "md5" {
+		t.Fatalf("Unexpected hashes: %+v", f.Hashes)
+	}
+	if len(f.URLs) != 2 {
+		t.Fatalf("Expected 2 urls, got %d", len(f.URLs))
+	}
+	if u := f.URLs[0]; u.URL != "http://m1.example.org/a/b.iso" || u.Priority != 1 || u.Location != "cn" {
+		t.Fatalf("Unexpected url: %+v", u)
+	}
+	if u := f.URLs[1]; u.URL != "http://m2.example.org/repo/a/b.iso" || u.Priority != 2 || u.Location != "fr" {
+		t.Fatalf("Unexpected url: %+v", u)
+	}
+
+	rec = httptest.NewRecorder()
+	ctx = NewContext(rec, httptest.NewRequest("GET", "/a/b.iso.meta4", nil), Templates{})
+	if status, _ := (&MetalinkRenderer{}).Write(ctx, &mirrors.Results{}); status != http.StatusNotFound {
+		t.Fatalf("Expected a 404, got %d", status)
+	}
+}
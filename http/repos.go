@@ -0,0 +1,250 @@
+// Copyright (c) 2014-2019 Ludovic Fauvet
+// Licensed under the MIT license
+
+package http
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	. "github.com/opensourceways/mirrorbits/config"
+	"github.com/opensourceways/mirrorbits/core"
+	"github.com/opensourceways/mirrorbits/filesystem"
+	"github.com/opensourceways/mirrorbits/mirrors"
+)
+
+const (
+	// maxRepoMirrors is the maximum number of mirrors listed for a dnf repository
+	maxRepoMirrors = 20
+)
+
+// repoMetalink is a metalink in the version 3.0 format expected by dnf
+// for the metadata of a repository
+type repoMetalink struct {
+	XMLName   xml.Name         `xml:"http://www.metalinker.org/ metalink"`
+	Version   string           `xml:"version,attr"`
+	Type      string           `xml:"type,attr"`
+	PubDate   string           `xml:"pubdate,attr"`
+	Generator string           `xml:"generator,attr"`
+	MM0       string           `xml:"xmlns:mm0,attr"`
+	File      repoMetalinkFile `xml:"files>file"`
+}
+
+type repoMetalinkFile struct {
+	Name      string            `xml:"name,attr"`
+	Timestamp int64             `xml:"mm0:timestamp,omitempty"`
+	Size      int64             `xml:"size"`
+	Hashes    []metalinkHash    `xml:"verification>hash"`
+	URLs      []repoMetalinkURL `xml:"resources>url"`
+}
+
+type repoMetalinkURL struct {
+	Protocol   string `xml:"protocol,attr"`
+	Type       string `xml:"type,attr"`
+	Location   string `xml:"location,attr,omitempty"`
+	Preference int    `xml:"preference,attr"`
+	URL        string `xml:",chardata"`
+}
+
+// repoMetalinkHandler returns the metalink of the metadata of the requested
+// dnf repository, i.e. /metalink?repo=<version>-<name>&arch=<arch>
+func (h *HTTP) repoMetalinkHandler(w http.ResponseWriter, r *http.Request) {
+	dir, fileInfo, mlist, ok := h.repoMirrors(w, r)
+	if !ok {
+		return
+	}
+
+	output, err := repoMetalinkDocument(dir, fileInfo, mlist)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "private, no-cache")
+	w.Header().Set("Content-Type", "application/metalink+xml")
+	w.Header().Set("Content-Length", strconv.Itoa(len(output)))
+	w.Write(output)
+}
+
+// repoMirrorlistHandler returns the base URLs of the mirrors serving the
+// requested dnf repository, i.e. /mirrorlist?repo=<version>-<name>&arch=<arch>
+func (h *HTTP) repoMirrorlistHandler(w http.ResponseWriter, r *http.Request) {
+	dir, _, mlist, ok := h.repoMirrors(w, r)
+	if !ok {
+		return
+	}
+
+	output := repoMirrorlistDocument(dir, mlist)
+
+	w.Header().Set("Cache-Control", "private, no-cache")
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Content-Length", strconv.Itoa(len(output)))
+	w.Write(output)
+}
+
+// repoMirrors returns the directory of the requested dnf repository, the
+// information of its metadata and the ranked mirrors serving them. The
+// error is written to the client if ok is false.
+func (h *HTTP) repoMirrors(w http.ResponseWriter, r *http.Request) (dir string, fileInfo filesystem.FileInfo, mlist mirrors.Mirrors, ok bool) {
+	cnf := GetConfig()
+
+	h.templates.RLock()
+	ctx := NewContext(w, r, h.templates)
+	h.templates.RUnlock()
+
+	repo, arch := ctx.QueryParam("repo"), ctx.QueryParam("arch")
+	if !isRepoParam(repo) || !isRepoParam(arch) {
+		http.Error(w, "Invalid repo or arch", http.StatusBadRequest)
+		return
+	}
+
+	for _, d := range repoCandidates(repo, arch, cnf) {
+		f, err := h.cache.GetFileInfo(d + filesystem.Sep + filesystem.RepoMetadataFile)
+		if err != nil {
+			log.Errorf("Error while fetching Fileinfo: %s", err.Error())
+			http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+			return
+		}
+		if f.Size > 0 {
+			dir, fileInfo = d, f
+			break
+		}
+	}
+	if dir == "" {
+		http.NotFound(w, r)
+		return
+	}
+
+	clientInfo := h.geoip.GetRecord(clientIP(r))
+	if !clientInfo.IsValid() {
+		h.stats.CountUnlocated(unlocatedPolicy(cnf))
+		clientInfo = locateUnlocated(clientInfo, cnf)
+	}
+
+	mlist, err := h.cache.GetMirrors(fileInfo.Path, clientInfo)
+	if err == nil && len(mlist) > 0 {
+		mlist, _, err = h.engine.Selection(ctx, &fileInfo, clientInfo, mlist, cnf)
+	}
+	if _, isNetErr := err.(net.Error); isNetErr || len(mlist) == 0 {
+		mlist = mirrors.SelectFallbacks(clientInfo)
+		if len(mlist) == 0 {
+			log.Errorf("Unable to serve the repository %s: no mirror available", dir)
+			http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+			return
+		}
+	} else if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if len(mlist) > maxRepoMirrors {
+		mlist = mlist[:maxRepoMirrors]
+	}
+	return dir, fileInfo, mlist, true
+}
+
+// isRepoParam returns true if the parameter can be used within a path
+func isRepoParam(param string) bool {
+	return param != "" && !strings.Contains(param, filesystem.Sep) && !strings.Contains(param, "..")
+}
+
+// repoCandidates returns the possible directories of the dnf repository
+// <version>-<name> for the given architecture, the versions and the names
+// both possibly containing dashes. The shortest names come first.
+func repoCandidates(repo, arch string, cnf *Configuration) []string {
+	if !strings.HasPrefix(repo, filesystem.RepoVersionDirectoryPrefix) {
+		return nil
+	}
+	var dirs []string
+	for i := len(repo) - 2; i > len(filesystem.RepoVersionDirectoryPrefix); i-- {
+		if repo[i] == '-' {
+			dirs = append(dirs, repoDir(repo[:i], repo[i+1:], arch, cnf))
+		}
+	}
+	return dirs
+}
+
+// repoDir returns the directory of a dnf repository, following its configured
+// layout if any, <name>/<arch> otherwise
+func repoDir(version, name, arch string, cnf *Configuration) string {
+	layout := name + filesystem.Sep + "$arch"
+	for _, r := range cnf.DnfRepos {
+		if r.Name == name {
+			layout = r.Path
+			break
+		}
+	}
+	return version + filesystem.Sep + strings.Trim(strings.ReplaceAll(layout, "$arch", arch), filesystem.Sep)
+}
+
+// repoMetalinkDocument renders the metalink of the metadata of a dnf repository
+func repoMetalinkDocument(dir string, fileInfo filesystem.FileInfo, mlist mirrors.Mirrors) ([]byte, error) {
+	file := repoMetalinkFile{
+		Name: "repomd.xml",
+		Size: fileInfo.Size,
+	}
+	if !fileInfo.ModTime.IsZero() {
+		file.Timestamp = fileInfo.ModTime.Unix()
+	}
+	for _, h := range []metalinkHash{
+		{Type: "md5", Value: fileInfo.Md5},
+		{Type: "sha1", Value: fileInfo.Sha1},
+		{Type: "sha256", Value: fileInfo.Sha256},
+	} {
+		if h.Value != "" {
+			file.Hashes = append(file.Hashes, h)
+		}
+	}
+
+	// The highest preference being the preferred one
+	for i, m := range mlist {
+		protocol := "http"
+		if m.IsHTTPS() {
+			protocol = "https"
+		}
+		var countryCode string
+		if len(m.CountryFields) > 0 {
+			countryCode = m.CountryFields[0]
+		}
+		preference := 100 - i
+		if preference < 1 {
+			preference = 1
+		}
+		file.URLs = append(file.URLs, repoMetalinkURL{
+			Protocol:   protocol,
+			Type:       protocol,
+			Location:   countryCode,
+			Preference: preference,
+			URL:        m.HttpURL + dir + filesystem.Sep + filesystem.RepoMetadataFile,
+		})
+	}
+
+	output, err := xml.MarshalIndent(repoMetalink{
+		Version:   "3.0",
+		Type:      "dynamic",
+		PubDate:   time.Now().UTC().Format(http.TimeFormat),
+		Generator: "Mirrorbits/" + core.VERSION,
+		MM0:       "http://fedorahosted.org/mirrormanager",
+		File:      file,
+	}, "", " ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), output...), nil
+}
+
+// repoMirrorlistDocument renders the base URLs of a dnf repository, one per line
+func repoMirrorlistDocument(dir string, mlist mirrors.Mirrors) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "# %s\n", dir)
+	for _, m := range mlist {
+		fmt.Fprintf(&buf, "%s%s/\n", m.HttpURL, dir)
+	}
+	return buf.Bytes()
+}
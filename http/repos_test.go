@@ -0,0 +1,83 @@
+// Copyright (c) 2014-2019 Ludovic Fauvet
+// Licensed under the MIT license
+
+package http
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/opensourceways/mirrorbits/config"
+	"github.com/opensourceways/mirrorbits/filesystem"
+	"github.com/opensourceways/mirrorbits/mirrors"
+)
+
+func TestRepoCandidates(t *testing.T) {
+	cnf := &Configuration{
+		DnfRepos: []DnfRepo{{Name: "EPOL", Path: "EPOL/main/$arch"}},
+	}
+
+	dirs := repoCandidates("openEuler-22.03-LTS-SP1-OS", "x86_64", cnf)
+	expected := []string{
+		"openEuler-22.03-LTS-SP1/OS/x86_64",
+		"openEuler-22.03-LTS/SP1-OS/x86_64",
+		"openEuler-22.03/LTS-SP1-OS/x86_64",
+	}
+	if strings.Join(dirs, ",") != strings.Join(expected, ",") {
+		t.Fatalf("Unexpected candidates: %v", dirs)
+	}
+
+	if dirs = repoCandidates("openEuler-24.03-LTS-EPOL", "aarch64", cnf); dirs[0] != "openEuler-24.03-LTS/EPOL/main/aarch64" {
+		t.Fatalf("Expected the configured layout, got %s", dirs[0])
+	}
+
+	if dirs = repoCandidates("fedora-40-OS", "x86_64", cnf); len(dirs) != 0 {
+		t.Fatalf("Expected no candidate outside of the repo versions, got %v", dirs)
+	}
+
+	for _, p := range []string{"", "../OS", "OS/x86_64"} {
+		if isRepoParam(p) {
+			t.Fatalf("Expected %q to be rejected", p)
+		}
+	}
+}
+
+func TestRepoDocuments(t *testing.T) {
+	dir := "openEuler-22.03-LTS/OS/x86_64"
+	fileInfo := filesystem.FileInfo{
+		Path:    dir + "/repodata/repomd.xml",
+		Size:    3042,
+		ModTime: time.Unix(1700000000, 0),
+		Sha256:  "abcd",
+	}
+	mlist := mirrors.Mirrors{
+		{ID: 1, HttpURL: "https://m1.example.org/", CountryFields: []string{"CN"}},
+		{ID: 2, HttpURL: "http://m2.example.org/openeuler/", CountryFields: []string{"FR"}},
+	}
+
+	output, err := repoMetalinkDocument(dir, fileInfo, mlist)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	doc := string(output)
+	for _, s := range []string{
+		`<metalink xmlns="http://www.metalinker.org/" version="3.0" type="dynamic"`,
+		`xmlns:mm0="http://fedorahosted.org/mirrormanager"`,
+		`<file name="repomd.xml">`,
+		`<mm0:timestamp>1700000000</mm0:timestamp>`,
+		`<size>3042</size>`,
+		`<hash type="sha256">abcd</hash>`,
+		`<url protocol="https" type="https" location="CN" preference="100">https://m1.example.org/openEuler-22.03-LTS/OS/x86_64/repodata/repomd.xml</url>`,
+		`<url protocol="http" type="http" location="FR" preference="99">http://m2.example.org/openeuler/openEuler-22.03-LTS/OS/x86_64/repodata/repomd.xml</url>`,
+	} {
+		if !strings.Contains(doc, s) {
+			t.Fatalf("Expected %s in the metalink:\n%s", s, doc)
+		}
+	}
+
+	list := string(repoMirrorlistDocument(dir, mlist))
+	if list != "# openEuler-22.03-LTS/OS/x86_64\nhttps://m1.example.org/openEuler-22.03-LTS/OS/x86_64/\nhttp://m2.example.org/openeuler/openEuler-22.03-LTS/OS/x86_64/\n" {
+		t.Fatalf("Unexpected mirrorlist:\n%s", list)
+	}
+}
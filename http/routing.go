@@ -0,0 +1,139 @@
+// Copyright (c) 2014-2019 Ludovic Fauvet
+// Licensed under the MIT license
+
+package http
+
+import (
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/opensourceways/mirrorbits/database"
+	"github.com/opensourceways/mirrorbits/mirrors"
+	"github.com/opensourceways/mirrorbits/network"
+)
+
+const (
+	// routingRefresh is the interval between two full rebuilds of the routing table
+	routingRefresh = time.Minute
+)
+
+// routingTable holds the network rules of all the mirrors in a prefix trie
+type routingTable struct {
+	r       *database.Redis
+	lock    sync.RWMutex
+	trie    *network.Trie
+	updates chan string
+	stop    chan struct{}
+}
+
+type routingEntry struct {
+	mirrorID int
+	rule     mirrors.NetworkRule
+}
+
+func newRoutingTable(r *database.Redis) *routingTable {
+	t := &routingTable{
+		r:       r,
+		trie:    network.NewTrie(),
+		updates: make(chan string, 10),
+		stop:    make(chan struct{}),
+	}
+	if r.Pubsub != nil {
+		r.Pubsub.SubscribeEvent(database.MIRROR_UPDATE, t.updates)
+	}
+	go t.refreshLoop()
+	return t
+}
+
+// Stop terminates the refresh of the routing table
+func (t *routingTable) Stop() {
+	close(t.stop)
+}
+
+func (t *routingTable) refreshLoop() {
+	ticker := time.NewTicker(routingRefresh)
+	defer ticker.Stop()
+
+	for {
+		if err := t.refresh(); err != nil && !t.r.Failure() {
+			log.Warningf("Routing: unable to load the network rules: %s", err)
+		}
+		select {
+		case <-t.stop:
+			return
+		case <-t.updates:
+		case <-ticker.C:
+		}
+	}
+}
+
+// refresh rebuilds the trie from the network rules of all the mirrors
+func (t *routingTable) refresh() error {
+	mlist, err := t.r.GetListOfMirrors()
+	if err != nil {
+		return err
+	}
+
+	rconn := t.r.Get()
+	defer rconn.Close()
+
+	ids := make([]int, 0, len(mlist))
+	rconn.Send("MULTI")
+	for id := range mlist {
+		ids = append(ids, id)
+		rconn.Send("HGET", fmt.Sprintf("MIRROR_%d", id), "networks")
+	}
+	res, err := redis.Values(rconn.Do("EXEC"))
+	if err != nil {
+		return err
+	}
+
+	trie := network.NewTrie()
+	for i, reply := range res {
+		if reply == nil {
+			continue
+		}
+		var rules mirrors.NetworkRules
+		if err := rules.RedisScan(reply); err != nil {
+			log.Warningf("Routing: invalid network rules for mirror #%d: %s", ids[i], err)
+			continue
+		}
+		for _, rule := range rules {
+			n, err := mirrors.ParseNetwork(rule.Network)
+			if err != nil {
+				continue
+			}
+			trie.Insert(n, routingEntry{mirrorID: ids[i], rule: rule})
+		}
+	}
+
+	t.lock.Lock()
+	t.trie = trie
+	t.lock.Unlock()
+	return nil
+}
+
+// Match returns the most specific network rule of each mirror matching the address
+func (t *routingTable) Match(ip net.IP) map[int]mirrors.NetworkRule {
+	if t == nil || ip == nil {
+		return nil
+	}
+
+	t.lock.RLock()
+	values := t.trie.Lookup(ip)
+	t.lock.RUnlock()
+
+	if len(values) == 0 {
+		return nil
+	}
+	matches := make(map[int]mirrors.NetworkRule, len(values))
+	for _, v := range values {
+		// Values are ordered from the least to the most specific network
+		e := v.(routingEntry)
+		matches[e.mirrorID] = e.rule
+	}
+	return matches
+}
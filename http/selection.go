@@ -5,7 +5,6 @@ package http
 
 import (
 	"fmt"
-	"math"
 	"math/rand"
 	"sort"
 	"strings"
@@ -18,6 +17,10 @@ import (
 	"github.com/opensourceways/mirrorbits/utils"
 )
 
+// minWeightDistance is the minimum range in km of the weight distribution,
+// the closest mirror possibly being in the same place as the client
+const minWeightDistance = 100
+
 type mirrorSelection interface {
 	// Selection must return an ordered list of selected mirror,
 	// a list of rejected mirrors and and an error code.
@@ -25,7 +28,30 @@ type mirrorSelection interface {
 }
 
 // DefaultEngine is the default algorithm used for mirror selection
-type DefaultEngine struct{}
+type DefaultEngine struct {
+	// rand is the source of the random distributions, the global
+	// source is used when nil. Only meant to be set by Seed for tests
+	// since a rand.Rand is not safe for concurrent use.
+	rand *rand.Rand
+	// bandwidth provides the estimated traffic sent to the mirrors
+	bandwidth *bandwidthTracker
+	// shares provides the share of the requests redirected to the mirrors
+	shares *shareTracker
+	// routing provides the network rules matching the client
+	routing *routingTable
+}
+
+// Seed makes the random distributions of the engine deterministic
+func (h *DefaultEngine) Seed(seed int64) {
+	h.rand = rand.New(rand.NewSource(seed))
+}
+
+func (h DefaultEngine) int63n(n int64) int64 {
+	if h.rand != nil {
+		return h.rand.Int63n(n)
+	}
+	return rand.Int63n(n)
+}
 
 // Selection returns an ordered list of selected mirror, a list of rejected mirrors and and an error code
 func (h DefaultEngine) Selection(ctx *Context, fileInfo *filesystem.FileInfo, clientInfo network.GeoIPRecord, pMirrors mirrors.Mirrors, cnf *Configuration) (mlist mirrors.Mirrors, excluded mirrors.Mirrors, err error) {
@@ -35,7 +61,17 @@ func (h DefaultEngine) Selection(ctx *Context, fileInfo *filesystem.FileInfo, cl
 	excluded = make([]mirrors.Mirror, 0, len(mlist))
 	var closestMirror float32
 	var farthestMirror float32
-	for i, m := range mlist {
+	now := time.Now()
+	version := filesystem.GetRepoVersion(fileInfo.Path)
+	matches := h.routing.Match(clientInfo.IP)
+	for _, m := range mlist {
+		// Is it reserved to the client's network?
+		if rule, ok := matches[m.ID]; ok {
+			m.NetworkMatch = rule.Network
+		} else if m.Private {
+			// Private mirrors are not even listed as excluded
+			continue
+		}
 		// Does it support http? Is it well formated?
 		if !strings.HasPrefix(m.HttpURL, "http://") && !strings.HasPrefix(m.HttpURL, "https://") {
 			m.ExcludeReason = "Invalid URL"
@@ -46,6 +82,11 @@ func (h DefaultEngine) Selection(ctx *Context, fileInfo *filesystem.FileInfo, cl
 			m.ExcludeReason = "Disabled"
 			goto discard
 		}
+		// Is it in a maintenance window?
+		if m.Maintenance.Active(now) != nil {
+			m.ExcludeReason = "In maintenance"
+			goto discard
+		}
 		// Is it up?
 		if !m.Up {
 			if m.ExcludeReason == "" {
@@ -53,6 +94,11 @@ func (h DefaultEngine) Selection(ctx *Context, fileInfo *filesystem.FileInfo, cl
 			}
 			goto discard
 		}
+		// Does it serve the requested version?
+		if version != "" && !m.HasVersion(version) {
+			m.ExcludeReason = "Version not available"
+			goto discard
+		}
 		if cnf.SchemaStrictMatch {
 			if ctx.SecureOption() == WITHTLS && !m.IsHTTPS() {
 				m.ExcludeReason = "Not HTTPS"
@@ -108,15 +154,7 @@ func (h DefaultEngine) Selection(ctx *Context, fileInfo *filesystem.FileInfo, cl
 			m.ExcludeReason = "User's country restriction"
 			goto discard
 		}
-		if safeIndex == 0 {
-			closestMirror = m.Distance
-		} else if closestMirror > m.Distance {
-			closestMirror = m.Distance
-		}
-		if m.Distance > farthestMirror {
-			farthestMirror = m.Distance
-		}
-		mlist[safeIndex] = mlist[i]
+		mlist[safeIndex] = m
 		safeIndex++
 		continue
 	discard:
@@ -126,27 +164,84 @@ func (h DefaultEngine) Selection(ctx *Context, fileInfo *filesystem.FileInfo, cl
 	// Reduce the slice to its new size
 	mlist = mlist[:safeIndex]
 
+	// Only keep the mirrors bound to the client's network by an exclusive
+	// rule if any of them is available
+	for _, m := range mlist {
+		if matches[m.ID].Exclusive {
+			kept := mlist[:0]
+			for _, m := range mlist {
+				if matches[m.ID].Exclusive {
+					kept = append(kept, m)
+				} else {
+					m.ExcludeReason = "Network rule"
+					excluded = append(excluded, m)
+				}
+			}
+			mlist = kept
+			break
+		}
+	}
+
+	// Only keep the mirrors of the client's ASN group if the group is
+	// exclusive and any of them is available
+	for i := range mlist {
+		// The group is derived from the AS number as the groups
+		// may have been reconfigured since the mirror was tagged
+		mlist[i].ASNGroup = network.ASNGroupOf(mlist[i].Asnum)
+		if clientInfo.ASNGroup != "" && mlist[i].ASNGroup == clientInfo.ASNGroup {
+			mlist[i].ASNGroupMatch = mlist[i].ASNGroup
+		}
+	}
+	if network.IsExclusiveASNGroup(clientInfo.ASNGroup) {
+		for _, m := range mlist {
+			if m.ASNGroupMatch != "" {
+				kept := mlist[:0]
+				for _, m := range mlist {
+					if m.ASNGroupMatch != "" || m.NetworkMatch != "" {
+						kept = append(kept, m)
+					} else {
+						m.ExcludeReason = "ASN group"
+						excluded = append(excluded, m)
+					}
+				}
+				mlist = kept
+				break
+			}
+		}
+	}
+
+	for i, m := range mlist {
+		if i == 0 || closestMirror > m.Distance {
+			closestMirror = m.Distance
+		}
+		if m.Distance > farthestMirror {
+			farthestMirror = m.Distance
+		}
+	}
+
+	for i := range mlist {
+		mlist[i].Utilization = h.bandwidth.Utilization(&mlist[i])
+		mlist[i].ShareCapped = h.shares.Capped(&mlist[i], clientInfo)
+	}
+
 	if !clientInfo.IsValid() {
-		// Shuffle the list
-		//XXX Should we use the fallbacks instead?
-		for i := range mlist {
-			j := rand.Intn(i + 1)
-			mlist[i], mlist[j] = mlist[j], mlist[i]
+		mlist, excluded = h.selectUnlocated(ctx, mlist, excluded, cnf)
+
+		mlist = preferASNGroupMatches(mlist)
+		if cnf.BandwidthThreshold > 0 {
+			mlist = steerOverloaded(mlist, cnf.BandwidthThreshold)
 		}
+		mlist = steerCapped(mlist)
+		mlist = preferNetworkMatches(mlist)
 
 		// Shortcut
-		if !ctx.IsMirrorlist() {
+		if !ctx.IsMirrorlist() && !ctx.IsAPI() {
 			// Reduce the number of mirrors to process
 			mlist = mlist[:utils.Min(5, len(mlist))]
 		}
 		return
 	}
 
-	// We're not interested in divisions by zero
-	if closestMirror == 0 {
-		closestMirror = math.SmallestNonzeroFloat32
-	}
-
 	/* Weight distribution for random selection [Probabilistic weight] */
 
 	// Compute score for each mirror and return the mirrors eligible for weight distribution.
@@ -160,6 +255,10 @@ func (h DefaultEngine) Selection(ctx *Context, fileInfo *filesystem.FileInfo, cl
 		var countryScore, netRateScore, distanceScore int
 		distanceScore = baseScore - int(m.Distance) + 1
 		netRateScore = m.Score
+		if m.SubdivisionCode != "" && m.SubdivisionCode == clientInfo.SubdivisionCode {
+			// Subdivision affinity bonus
+			netRateScore += cnf.SubdivisionWeight
+		}
 		if utils.IsPrimaryCountry(clientInfo, m.CountryFields) {
 			countryScore = 1
 		}
@@ -174,5 +273,148 @@ func (h DefaultEngine) Selection(ctx *Context, fileInfo *filesystem.FileInfo, cl
 	// Sort mirrors by computed score
 	sort.Sort(mirrors.ByComputedScore{Mirrors: mlist})
 
+	if cnf.SelectionMode == "weighted" {
+		maxDistance := closestMirror * cnf.WeightDistributionRange
+		if maxDistance < minWeightDistance {
+			maxDistance = minWeightDistance
+		}
+		mlist = h.weightedDistribution(ctx, mlist, maxDistance)
+	}
+
+	// Mirrors of the client's ASN group come before the others
+	mlist = preferASNGroupMatches(mlist)
+
+	if cnf.BandwidthThreshold > 0 {
+		mlist = steerOverloaded(mlist, cnf.BandwidthThreshold)
+	}
+
+	// Mirrors having reached their share come after the others
+	mlist = steerCapped(mlist)
+
+	// Mirrors matching the client's network always come first
+	mlist = preferNetworkMatches(mlist)
+
 	return
 }
+
+// steerOverloaded moves the mirrors whose estimated utilization exceeds
+// the threshold after the others, keeping the relative order of both groups.
+func steerOverloaded(mlist mirrors.Mirrors, threshold float32) mirrors.Mirrors {
+	return partition(mlist, func(m *mirrors.Mirror) bool {
+		return m.Utilization <= threshold
+	})
+}
+
+// steerCapped moves the mirrors having reached one of their share caps
+// after the others, keeping the relative order of both groups.
+func steerCapped(mlist mirrors.Mirrors) mirrors.Mirrors {
+	return partition(mlist, func(m *mirrors.Mirror) bool {
+		return m.ShareCapped == ""
+	})
+}
+
+// preferASNGroupMatches moves the mirrors of the ASN group of the client
+// before the others, keeping the relative order of both groups.
+func preferASNGroupMatches(mlist mirrors.Mirrors) mirrors.Mirrors {
+	return partition(mlist, func(m *mirrors.Mirror) bool {
+		return m.ASNGroupMatch != ""
+	})
+}
+
+// preferNetworkMatches moves the mirrors matching a network rule of the
+// client before the others, keeping the relative order of both groups.
+func preferNetworkMatches(mlist mirrors.Mirrors) mirrors.Mirrors {
+	return partition(mlist, func(m *mirrors.Mirror) bool {
+		return m.NetworkMatch != ""
+	})
+}
+
+// partition is a stable partition of the mirrors, the ones for which
+// first returns true being moved before the others.
+func partition(mlist mirrors.Mirrors, first func(*mirrors.Mirror) bool) mirrors.Mirrors {
+	var head, tail mirrors.Mirrors
+	for i := range mlist {
+		if first(&mlist[i]) {
+			head = append(head, mlist[i])
+		} else {
+			tail = append(tail, mlist[i])
+		}
+	}
+	if len(head) == 0 || len(tail) == 0 {
+		return mlist
+	}
+	return append(head, tail...)
+}
+
+// weightedDistribution randomly reorders the mirrors found within maxDistance
+// from the client and sharing the country score of the first mirror, each of
+// them having a probability proportional to its weight to come first. The
+// other mirrors are kept after them in the same order.
+func (h DefaultEngine) weightedDistribution(ctx *Context, mlist mirrors.Mirrors, maxDistance float32) mirrors.Mirrors {
+	if len(mlist) == 0 {
+		return mlist
+	}
+	countryScore := mlist[0].ComputedScore[0]
+	eligible := func(m mirrors.Mirror) bool {
+		return m.ComputedScore[0] == countryScore && m.Distance <= maxDistance
+	}
+
+	var selected, rest mirrors.Mirrors
+	var total int64
+	for _, m := range mlist {
+		if eligible(m) {
+			selected = append(selected, m)
+			total += mirrorWeight(m)
+		} else {
+			rest = append(rest, m)
+		}
+	}
+
+	if len(selected) == 0 {
+		return mlist
+	}
+
+	if ctx.IsMirrorlist() {
+		// Don't reorder the results, just set the percentage
+		for i := range mlist {
+			if eligible(mlist[i]) {
+				mlist[i].Weight = float32(float64(mirrorWeight(mlist[i])) * 100 / float64(total))
+			}
+		}
+		return mlist
+	}
+
+	// Weighted random sampling without replacement
+	weighted := make(mirrors.Mirrors, 0, len(mlist))
+	remaining := total
+	for len(selected) > 0 {
+		r := h.int63n(remaining)
+		for i := range selected {
+			w := mirrorWeight(selected[i])
+			if r < w {
+				selected[i].Weight = float32(float64(w) * 100 / float64(total))
+				weighted = append(weighted, selected[i])
+				selected = append(selected[:i], selected[i+1:]...)
+				remaining -= w
+				break
+			}
+			r -= w
+		}
+	}
+
+	return append(weighted, rest...)
+}
+
+// mirrorWeight returns the weight of a mirror in the random distribution
+// based on its score and its network bandwidth. The minimum weight is 1.
+func mirrorWeight(m mirrors.Mirror) int64 {
+	score := int64(m.Score)
+	if score < 1 {
+		score = 1
+	}
+	bandwidth := int64(m.NetworkBandwidth)
+	if bandwidth < 1 {
+		bandwidth = 1
+	}
+	return score * bandwidth
+}
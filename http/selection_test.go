@@ -0,0 +1,311 @@
+// Copyright (c) 2014-2019 Ludovic Fauvet
+// Licensed under the MIT license
+
+package http
+
+import (
+	"net"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/opensourceways/mirrorbits/config"
+	"github.com/opensourceways/mirrorbits/filesystem"
+	"github.com/opensourceways/mirrorbits/mirrors"
+	"github.com/opensourceways/mirrorbits/network"
+)
+
+func selectionTestMirrors() mirrors.Mirrors {
+	return mirrors.Mirrors{
+		{ID: 1, Name: "near-small", HttpURL: "http://m1/", Enabled: true, Up: true, Distance: 100, Score: 1, NetworkBandwidth: 100},
+		{ID: 2, Name: "near-big", HttpURL: "http://m2/", Enabled: true, Up: true, Distance: 120, Score: 1, NetworkBandwidth: 300},
+		{ID: 3, Name: "far", HttpURL: "http://m3/", Enabled: true, Up: true, Distance: 1000, Score: 10, NetworkBandwidth: 10000},
+	}
+}
+
+func TestDefaultEngine_WeightedSelection(t *testing.T) {
+	cnf := &Configuration{
+		WeightDistributionRange: 1.5,
+		SelectionMode:           "weighted",
+	}
+	clientInfo := network.GeoIPRecord{CountryCode: "FR", ContinentCode: "EU"}
+	fileInfo := &filesystem.FileInfo{Path: "/openEuler-22.03-LTS"}
+	ctx := NewContext(httptest.NewRecorder(), httptest.NewRequest("GET", "/openEuler-22.03-LTS", nil), Templates{})
+
+	engine := DefaultEngine{}
+	engine.Seed(42)
+
+	first := map[string]int{}
+	for i := 0; i < 4000; i++ {
+		mlist, _, err := engine.Selection(ctx, fileInfo, clientInfo, selectionTestMirrors(), cnf)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if len(mlist) != 3 {
+			t.Fatalf("Expected 3 mirrors, got %d", len(mlist))
+		}
+		if mlist[2].Name != "far" {
+			t.Fatalf("Mirrors out of the distribution range must come last")
+		}
+		first[mlist[0].Name]++
+	}
+
+	// near-big should be selected 3 times more often than near-small
+	if first["near-big"] < 2800 || first["near-big"] > 3200 {
+		t.Fatalf("Unexpected distribution: %v", first)
+	}
+
+	// The same seed must give the same selection
+	a, b := DefaultEngine{}, DefaultEngine{}
+	a.Seed(1)
+	b.Seed(1)
+	for i := 0; i < 10; i++ {
+		la, _, _ := a.Selection(ctx, fileInfo, clientInfo, selectionTestMirrors(), cnf)
+		lb, _, _ := b.Selection(ctx, fileInfo, clientInfo, selectionTestMirrors(), cnf)
+		if la[0].ID != lb[0].ID || la[1].ID != lb[1].ID {
+			t.Fatalf("Selection is not deterministic for a given seed")
+		}
+	}
+}
+
+func TestDefaultEngine_WeightedCountry(t *testing.T) {
+	cnf := &Configuration{
+		WeightDistributionRange: 1.5,
+		SelectionMode:           "weighted",
+	}
+	clientInfo := network.GeoIPRecord{CountryCode: "FR", ContinentCode: "EU"}
+	fileInfo := &filesystem.FileInfo{Path: "/openEuler-22.03-LTS"}
+	ctx := NewContext(httptest.NewRecorder(), httptest.NewRequest("GET", "/openEuler-22.03-LTS", nil), Templates{})
+
+	engine := DefaultEngine{}
+	engine.Seed(42)
+
+	// The mirrors of the client's country come first even if farther
+	for i := 0; i < 100; i++ {
+		mlist := selectionTestMirrors()
+		mlist[2].CountryFields = []string{"FR"}
+		mlist, _, _ = engine.Selection(ctx, fileInfo, clientInfo, mlist, cnf)
+		if mlist[0].Name != "far" {
+			t.Fatalf("Expected the mirror of the client's country first, got %s", mlist[0].Name)
+		}
+	}
+}
+
+func TestDefaultEngine_WeightedMinimumRange(t *testing.T) {
+	cnf := &Configuration{
+		WeightDistributionRange: 1.5,
+		SelectionMode:           "weighted",
+	}
+	clientInfo := network.GeoIPRecord{CountryCode: "FR", ContinentCode: "EU"}
+	fileInfo := &filesystem.FileInfo{Path: "/openEuler-22.03-LTS"}
+	ctx := NewContext(httptest.NewRecorder(), httptest.NewRequest("GET", "/openEuler-22.03-LTS", nil), Templates{})
+
+	engine := DefaultEngine{}
+	engine.Seed(42)
+
+	// A mirror at the location of the client doesn't take all the requests
+	first := map[string]int{}
+	for i := 0; i < 1000; i++ {
+		mlist := selectionTestMirrors()
+		mlist[0].Distance = 0
+		mlist[1].Distance = 50
+		mlist, _, _ = engine.Selection(ctx, fileInfo, clientInfo, mlist, cnf)
+		first[mlist[0].Name]++
+	}
+	if first["near-small"] == 0 || first["near-big"] == 0 || first["far"] != 0 {
+		t.Fatalf("Unexpected distribution: %v", first)
+	}
+}
+
+func TestDefaultEngine_WeightedMirrorlist(t *testing.T) {
+	cnf := &Configuration{
+		WeightDistributionRange: 1.5,
+		SelectionMode:           "weighted",
+	}
+	clientInfo := network.GeoIPRecord{CountryCode: "FR", ContinentCode: "EU"}
+	fileInfo := &filesystem.FileInfo{Path: "/openEuler-22.03-LTS"}
+	ctx := NewContext(httptest.NewRecorder(), httptest.NewRequest("GET", "/openEuler-22.03-LTS?mirrorlist", nil), Templates{})
+
+	mlist, _, err := DefaultEngine{}.Selection(ctx, fileInfo, clientInfo, selectionTestMirrors(), cnf)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	weights := map[string]float32{}
+	for _, m := range mlist {
+		weights[m.Name] = m.Weight
+	}
+	if weights["near-small"] != 25 || weights["near-big"] != 75 || weights["far"] != 0 {
+		t.Fatalf("Unexpected weights: %v", weights)
+	}
+}
+
+func TestDefaultEngine_ScoreSelection(t *testing.T) {
+	cnf := &Configuration{
+		WeightDistributionRange: 1.5,
+		SelectionMode:           "score",
+	}
+	clientInfo := network.GeoIPRecord{CountryCode: "FR", ContinentCode: "EU"}
+	fileInfo := &filesystem.FileInfo{Path: "/openEuler-22.03-LTS"}
+	ctx := NewContext(httptest.NewRecorder(), httptest.NewRequest("GET", "/openEuler-22.03-LTS", nil), Templates{})
+
+	mlist, _, err := DefaultEngine{}.Selection(ctx, fileInfo, clientInfo, selectionTestMirrors(), cnf)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if mlist[0].Name != "far" || mlist[0].Weight != 0 {
+		t.Fatalf("The score mode must sort strictly by computed score, got %s first", mlist[0].Name)
+	}
+}
+
+func TestDefaultEngine_NetworkRules(t *testing.T) {
+	cnf := &Configuration{
+		WeightDistributionRange: 1.5,
+		SelectionMode:           "score",
+	}
+	fileInfo := &filesystem.FileInfo{Path: "/openEuler-22.03-LTS"}
+	ctx := NewContext(httptest.NewRecorder(), httptest.NewRequest("GET", "/openEuler-22.03-LTS", nil), Templates{})
+
+	trie := network.NewTrie()
+	for _, e := range []routingEntry{
+		{mirrorID: 2, rule: mirrors.NetworkRule{Network: "10.0.0.0/8"}},
+		{mirrorID: 4, rule: mirrors.NetworkRule{Network: "10.1.0.0/16", Exclusive: true}},
+	} {
+		n, _ := mirrors.ParseNetwork(e.rule.Network)
+		trie.Insert(n, e)
+	}
+	engine := DefaultEngine{routing: &routingTable{trie: trie}}
+
+	mlist := append(selectionTestMirrors(), mirrors.Mirror{
+		ID: 4, Name: "campus", HttpURL: "http://m4/", Enabled: true, Up: true, Distance: 500, Private: true,
+	})
+
+	// Preferred mirror
+	clientInfo := network.GeoIPRecord{CountryCode: "FR", ContinentCode: "EU", IP: net.ParseIP("10.2.0.1")}
+	selected, excluded, err := engine.Selection(ctx, fileInfo, clientInfo, append(mirrors.Mirrors{}, mlist...), cnf)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if len(selected) != 3 || selected[0].Name != "near-big" || selected[0].NetworkMatch != "10.0.0.0/8" {
+		t.Fatalf("The mirror matching the client's network must come first")
+	}
+	if len(excluded) != 0 {
+		t.Fatalf("Private mirrors must not be listed, got %v", excluded)
+	}
+
+	// Even when they are disabled or misconfigured
+	hidden := append(mirrors.Mirrors{}, mlist...)
+	hidden[3].Enabled = false
+	if _, excluded, _ = engine.Selection(ctx, fileInfo, clientInfo, hidden, cnf); len(excluded) != 0 {
+		t.Fatalf("Disabled private mirrors must not be listed, got %v", excluded)
+	}
+	hidden[3].HttpURL = "m4"
+	if _, excluded, _ = engine.Selection(ctx, fileInfo, clientInfo, hidden, cnf); len(excluded) != 0 {
+		t.Fatalf("Malformed private mirrors must not be listed, got %v", excluded)
+	}
+
+	// Exclusive mirror
+	clientInfo.IP = net.ParseIP("10.1.0.1")
+	selected, excluded, _ = engine.Selection(ctx, fileInfo, clientInfo, append(mirrors.Mirrors{}, mlist...), cnf)
+	if len(selected) != 1 || selected[0].Name != "campus" {
+		t.Fatalf("Only the exclusive mirror must be selected, got %v", selected)
+	}
+	if len(excluded) != 3 || excluded[0].ExcludeReason != "Network rule" {
+		t.Fatalf("The other mirrors must be excluded, got %v", excluded)
+	}
+
+	// Exclusive mirror down
+	mlist[3].Up = false
+	selected, _, _ = engine.Selection(ctx, fileInfo, clientInfo, append(mirrors.Mirrors{}, mlist...), cnf)
+	if len(selected) != 3 || selected[0].Name != "near-big" {
+		t.Fatalf("The other mirrors must be used when the exclusive one is unavailable")
+	}
+}
+
+func TestDefaultEngine_SubdivisionAffinity(t *testing.T) {
+	cnf := &Configuration{
+		WeightDistributionRange: 1.5,
+		SelectionMode:           "score",
+		SubdivisionWeight:       1,
+	}
+	clientInfo := network.GeoIPRecord{CountryCode: "FR", ContinentCode: "EU", SubdivisionCode: "FR-IDF"}
+	fileInfo := &filesystem.FileInfo{Path: "/openEuler-22.03-LTS"}
+	ctx := NewContext(httptest.NewRecorder(), httptest.NewRequest("GET", "/openEuler-22.03-LTS", nil), Templates{})
+
+	testMirrors := func() mirrors.Mirrors {
+		mlist := selectionTestMirrors()
+		mlist[1].SubdivisionCode = "FR-IDF"
+		return mlist
+	}
+
+	engine := DefaultEngine{}
+	mlist, _, _ := engine.Selection(ctx, fileInfo, clientInfo, testMirrors(), cnf)
+	if mlist[0].Name != "far" || mlist[1].Name != "near-big" {
+		t.Fatalf("Expected far then near-big, got %s then %s", mlist[0].Name, mlist[1].Name)
+	}
+	if mlist[1].ComputedScore[1] != 2 {
+		t.Fatalf("Expected a score of 2 for near-big, got %d", mlist[1].ComputedScore[1])
+	}
+
+	cnf.SubdivisionWeight = 20
+	mlist, _, _ = engine.Selection(ctx, fileInfo, clientInfo, testMirrors(), cnf)
+	if mlist[0].Name != "near-big" {
+		t.Fatalf("Expected near-big first, got %s", mlist[0].Name)
+	}
+
+	cnf.SubdivisionWeight = 0
+	mlist, _, _ = engine.Selection(ctx, fileInfo, clientInfo, testMirrors(), cnf)
+	if mlist[1].Name != "near-small" {
+		t.Fatalf("Expected no bonus without weight, got %s", mlist[1].Name)
+	}
+}
+
+func TestDefaultEngine_ASNGroups(t *testing.T) {
+	SetConfiguration(&Configuration{
+		ASNGroups: []ASNGroup{
+			{Name: "telecom", ASNums: []uint{4134}},
+			{Name: "unicom", ASNums: []uint{4837}},
+		},
+	})
+	cnf := &Configuration{
+		WeightDistributionRange: 1.5,
+		SelectionMode:           "score",
+	}
+	clientInfo := network.GeoIPRecord{CountryCode: "CN", ContinentCode: "AS", ASNum: 4134, ASNGroup: "telecom"}
+	fileInfo := &filesystem.FileInfo{Path: "/openEuler-22.03-LTS"}
+	ctx := NewContext(httptest.NewRecorder(), httptest.NewRequest("GET", "/openEuler-22.03-LTS", nil), Templates{})
+
+	testMirrors := func() mirrors.Mirrors {
+		mlist := selectionTestMirrors()
+		// Tagged before its AS number moved to another group
+		mlist[0].ASNGroup = "telecom"
+		mlist[0].Asnum = 4837
+		mlist[1].Asnum = 4134
+		return mlist
+	}
+
+	engine := DefaultEngine{}
+	mlist, _, _ := engine.Selection(ctx, fileInfo, clientInfo, testMirrors(), cnf)
+	if mlist[0].Name != "near-big" || mlist[0].ASNGroupMatch != "telecom" {
+		t.Fatalf("Expected near-big first, got %s", mlist[0].Name)
+	}
+	if mlist[1].Name != "far" || mlist[2].Name != "near-small" {
+		t.Fatalf("The order of the other mirrors must be preserved")
+	}
+
+	// Exclusive group
+	GetConfig().ASNGroups[0].Exclusive = true
+	mlist, excluded, _ := engine.Selection(ctx, fileInfo, clientInfo, testMirrors(), cnf)
+	if len(mlist) != 1 || mlist[0].Name != "near-big" {
+		t.Fatalf("Expected near-big only, got %d mirrors", len(mlist))
+	}
+	if len(excluded) != 2 || excluded[0].ExcludeReason != "ASN group" {
+		t.Fatalf("Expected the other mirrors to be excluded")
+	}
+
+	// Other clients are not affected
+	clientInfo = network.GeoIPRecord{CountryCode: "CN", ContinentCode: "AS", ASNum: 1234}
+	mlist, _, _ = engine.Selection(ctx, fileInfo, clientInfo, testMirrors(), cnf)
+	if len(mlist) != 3 || mlist[0].Name != "far" {
+		t.Fatalf("Expected far first, got %s", mlist[0].Name)
+	}
+}
@@ -0,0 +1,163 @@
+// Copyright (c) 2014-2019 Ludovic Fauvet
+// Licensed under the MIT license
+
+package http
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/opensourceways/mirrorbits/database"
+	"github.com/opensourceways/mirrorbits/mirrors"
+	"github.com/opensourceways/mirrorbits/network"
+)
+
+/*
+	Requests redirected to the mirrors by scope of the clients (see
+	mirrors.ShareScopes), shared by all the nodes of the cluster:
+	STATS_SHARES_[bucket]				= scope|mirror -> requests	By slice of bandwidthBucket seconds
+
+	The total of each scope is stored under scope|total. The buckets
+	share the sliding window of the bandwidth estimations.
+*/
+
+const shareTotal = "total"
+
+// shareTracker computes the share of the requests redirected to each mirror
+// during the sliding window, by scope of the clients
+type shareTracker struct {
+	r      *database.Redis
+	lock   sync.RWMutex
+	counts map[string]map[int]int64
+	totals map[string]int64
+	stop   chan struct{}
+}
+
+func newShareTracker(r *database.Redis) *shareTracker {
+	s := &shareTracker{
+		r:      r,
+		counts: make(map[string]map[int]int64),
+		totals: make(map[string]int64),
+		stop:   make(chan struct{}),
+	}
+	go s.refreshLoop()
+	return s
+}
+
+// Stop terminates the refresh of the shares
+func (s *shareTracker) Stop() {
+	close(s.stop)
+}
+
+func (s *shareTracker) refreshLoop() {
+	ticker := time.NewTicker(bandwidthRefresh)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+			if err := s.refresh(); err != nil && !s.r.Failure() {
+				log.Warningf("Shares: unable to refresh the counters: %s", err)
+			}
+		}
+	}
+}
+
+// refresh sums the requests of each mirror over the sliding window
+func (s *shareTracker) refresh() error {
+	rconn := s.r.Get()
+	defer rconn.Close()
+
+	window := bandwidthWindow()
+	last := bandwidthBucketOf(time.Now())
+
+	rconn.Send("MULTI")
+	for bucket := last - window + 1; bucket <= last; bucket++ {
+		rconn.Send("HGETALL", fmt.Sprintf("STATS_SHARES_%d", bucket))
+	}
+	res, err := redis.Values(rconn.Do("EXEC"))
+	if err != nil {
+		return err
+	}
+
+	counts := make(map[string]map[int]int64)
+	totals := make(map[string]int64)
+	for _, r := range res {
+		values, err := redis.Int64Map(r, nil)
+		if err != nil {
+			return err
+		}
+		for k, requests := range values {
+			separator := strings.LastIndex(k, "|")
+			if separator <= 0 {
+				continue
+			}
+			scope, object := k[:separator], k[separator+1:]
+			if object == shareTotal {
+				totals[scope] += requests
+				continue
+			}
+			mirrorID, err := strconv.Atoi(object)
+			if err != nil {
+				continue
+			}
+			if counts[scope] == nil {
+				counts[scope] = make(map[int]int64)
+			}
+			counts[scope][mirrorID] += requests
+		}
+	}
+
+	s.lock.Lock()
+	s.counts = counts
+	s.totals = totals
+	s.lock.Unlock()
+	return nil
+}
+
+// Share returns the percentage of the requests of the scope
+// redirected to the mirror during the sliding window
+func (s *shareTracker) Share(id int, scope string) float32 {
+	if s == nil {
+		return 0
+	}
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	total := s.totals[scope]
+	if total == 0 {
+		return 0
+	}
+	return float32(float64(s.counts[scope][id]) * 100 / float64(total))
+}
+
+// Capped returns the first share cap of the mirror reached for the
+// requests of the client, or an empty string if there's none.
+func (s *shareTracker) Capped(m *mirrors.Mirror, clientInfo network.GeoIPRecord) string {
+	for _, c := range m.ShareCaps {
+		if c.Applies(clientInfo) && s.Share(m.ID, c.Scope()) >= c.Share {
+			return c.String()
+		}
+	}
+	return ""
+}
+
+// Usage returns the state of all the share caps of the mirror
+func (s *shareTracker) Usage(m *mirrors.Mirror) []mirrors.ShareUsage {
+	var usage []mirrors.ShareUsage
+	for _, c := range m.ShareCaps {
+		share := s.Share(m.ID, c.Scope())
+		usage = append(usage, mirrors.ShareUsage{
+			Cap:    c.String(),
+			Share:  share,
+			Capped: share >= c.Share,
+		})
+	}
+	return usage
+}
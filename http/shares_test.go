@@ -0,0 +1,108 @@
+// Copyright (c) 2014-2019 Ludovic Fauvet
+// Licensed under the MIT license
+
+package http
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/opensourceways/mirrorbits/config"
+	"github.com/opensourceways/mirrorbits/filesystem"
+	"github.com/opensourceways/mirrorbits/mirrors"
+	"github.com/opensourceways/mirrorbits/network"
+	. "github.com/opensourceways/mirrorbits/testing"
+)
+
+func TestShareTracker_Refresh(t *testing.T) {
+	mock, conn := PrepareRedisTest()
+
+	SetConfiguration(&Configuration{
+		BandwidthWindow: 20,
+	})
+
+	mock.Command("MULTI").Expect("OK")
+	mock.GenericCommand("HGETALL").Expect([]interface{}{})
+	mock.Command("EXEC").Expect([]interface{}{
+		[]interface{}{
+			[]byte("all|1"), []byte("10"),
+			[]byte("all|total"), []byte("40"),
+			[]byte("country:CN|1"), []byte("10"),
+			[]byte("country:CN|total"), []byte("20"),
+		},
+		[]interface{}{
+			[]byte("all|1"), []byte("10"),
+			[]byte("all|total"), []byte("60"),
+		},
+	})
+
+	s := &shareTracker{r: conn}
+	if err := s.refresh(); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if share := s.Share(1, "all"); share != 20 {
+		t.Fatalf("Expected a share of 20%%, got %f", share)
+	}
+	if share := s.Share(1, "country:CN"); share != 50 {
+		t.Fatalf("Expected a share of 50%%, got %f", share)
+	}
+	if share := s.Share(2, "all"); share != 0 {
+		t.Fatalf("Expected a share of 0%%, got %f", share)
+	}
+
+	m := &mirrors.Mirror{ID: 1, ShareCaps: mirrors.ShareCaps{{Share: 30}, {Share: 40, Country: "CN"}}}
+	if capped := s.Capped(m, network.GeoIPRecord{CountryCode: "FR", ContinentCode: "EU"}); capped != "" {
+		t.Fatalf("Expected no cap for a french client, got %s", capped)
+	}
+	if capped := s.Capped(m, network.GeoIPRecord{CountryCode: "CN", ContinentCode: "AS"}); capped != "40% of CN" {
+		t.Fatalf("Expected the CN cap to be reached, got %q", capped)
+	}
+
+	usage := s.Usage(m)
+	if len(usage) != 2 || usage[0].Capped || !usage[1].Capped || usage[1].Share != 50 {
+		t.Fatalf("Unexpected usage %+v", usage)
+	}
+}
+
+func TestDefaultEngine_ShareCaps(t *testing.T) {
+	cnf := &Configuration{
+		WeightDistributionRange: 1.5,
+		SelectionMode:           "score",
+	}
+	fileInfo := &filesystem.FileInfo{Path: "/openEuler-22.03-LTS"}
+	ctx := NewContext(httptest.NewRecorder(), httptest.NewRequest("GET", "/openEuler-22.03-LTS", nil), Templates{})
+
+	// The mirror "far" got 30% of the requests of the french clients
+	engine := DefaultEngine{
+		shares: &shareTracker{
+			counts: map[string]map[int]int64{"country:FR": {3: 30}},
+			totals: map[string]int64{"country:FR": 100},
+		},
+	}
+
+	capped := func() mirrors.Mirrors {
+		mlist := selectionTestMirrors()
+		mlist[2].ShareCaps = mirrors.ShareCaps{{Share: 20, Country: "FR"}}
+		return mlist
+	}
+
+	clientInfo := network.GeoIPRecord{CountryCode: "FR", ContinentCode: "EU"}
+	mlist, _, err := engine.Selection(ctx, fileInfo, clientInfo, capped(), cnf)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if mlist[2].Name != "far" || mlist[2].ShareCapped != "20% of FR" {
+		t.Fatalf("Capped mirrors must come last, got %s", mlist[2].Name)
+	}
+	if mlist[0].Name != "near-small" || mlist[1].Name != "near-big" {
+		t.Fatalf("The order of the other mirrors must be preserved")
+	}
+
+	// The cap only applies to the french clients
+	clientInfo = network.GeoIPRecord{CountryCode: "DE", ContinentCode: "EU"}
+	mlist, _, _ = engine.Selection(ctx, fileInfo, clientInfo, capped(), cnf)
+	if mlist[0].Name != "far" || mlist[0].ShareCapped != "" {
+		t.Fatalf("Expected far to come first, got %s", mlist[0].Name)
+	}
+}
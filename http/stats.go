@@ -14,6 +14,7 @@ import (
 	"github.com/opensourceways/mirrorbits/database"
 	"github.com/opensourceways/mirrorbits/filesystem"
 	"github.com/opensourceways/mirrorbits/mirrors"
+	"github.com/opensourceways/mirrorbits/network"
 )
 
 /*
@@ -31,6 +32,26 @@ import (
 	STATS_MIRROR_[year]					= mirror -> value	By year
 	STATS_MIRROR_[year]_[month]			= mirror -> value	By month
 	STATS_MIRROR_[year]_[month]_[day]	= mirror -> value	By day
+
+	List of hashes for the fallbacks:
+	STATS_FALLBACK						= name -> value		All time
+	STATS_FALLBACK_[year]				= name -> value		By year
+	STATS_FALLBACK_[year]_[month]		= name -> value		By month
+	STATS_FALLBACK_[year]_[month]_[day]	= name -> value		By day
+
+	List of hashes for the files served locally:
+	STATS_LOCAL							= path -> value		All time
+	STATS_LOCAL_[year]					= path -> value		By year
+	STATS_LOCAL_[year]_[month]			= path -> value		By month
+	STATS_LOCAL_[year]_[month]_[day]	= path -> value		By day
+
+	STATS_LOCAL_BYTES_*					= path -> bytes		Same hierarchy
+
+	List of hashes for the clients without a GeoIP match:
+	STATS_UNLOCATED						= policy -> value	All time
+	STATS_UNLOCATED_[year]				= policy -> value	By year
+	STATS_UNLOCATED_[year]_[month]		= policy -> value	By month
+	STATS_UNLOCATED_[year]_[month]_[day]	= policy -> value	By day
 */
 
 var (
@@ -49,10 +70,14 @@ type Stats struct {
 }
 
 type countItem struct {
-	mirrorID int
-	filepath string
-	size     int64
-	time     time.Time
+	mirrorID  int
+	fallback  string
+	local     bool
+	unlocated string
+	scopes    []string
+	filepath  string
+	size      int64
+	time      time.Time
 }
 
 // NewStats returns an instance of the stats counter
@@ -75,7 +100,26 @@ func (s *Stats) Terminate() {
 }
 
 // CountDownload is a lightweight method used to count a new download for a specific file and mirror
-func (s *Stats) CountDownload(m mirrors.Mirror, fileinfo filesystem.FileInfo) error {
+func (s *Stats) CountDownload(m mirrors.Mirror, fileinfo filesystem.FileInfo, clientInfo network.GeoIPRecord) error {
+	if m.Name == "" {
+		return errUnknownMirror
+	}
+	if fileinfo.Path == "" {
+		return errEmptyFileError
+	}
+
+	s.countChan <- countItem{
+		mirrorID: m.ID,
+		scopes:   mirrors.ShareScopes(clientInfo),
+		filepath: fileinfo.Path,
+		size:     fileinfo.Size,
+		time:     time.Now().UTC(),
+	}
+	return nil
+}
+
+// CountFallback counts a download redirected to one of the fallbacks
+func (s *Stats) CountFallback(m mirrors.Mirror, fileinfo filesystem.FileInfo) error {
 	if m.Name == "" {
 		return errUnknownMirror
 	}
@@ -83,10 +127,38 @@ func (s *Stats) CountDownload(m mirrors.Mirror, fileinfo filesystem.FileInfo) er
 		return errEmptyFileError
 	}
 
-	s.countChan <- countItem{m.ID, fileinfo.Path, fileinfo.Size, time.Now().UTC()}
+	s.countChan <- countItem{
+		mirrorID: m.ID,
+		fallback: m.Name,
+		filepath: fileinfo.Path,
+		size:     fileinfo.Size,
+		time:     time.Now().UTC(),
+	}
 	return nil
 }
 
+// CountLocal counts a file served from the local repository,
+// sent being the number of bytes actually sent to the client
+func (s *Stats) CountLocal(path string, sent int64) error {
+	if path == "" {
+		return errEmptyFileError
+	}
+
+	s.countChan <- countItem{
+		local:    true,
+		filepath: path,
+		size:     sent,
+		time:     time.Now().UTC(),
+	}
+	return nil
+}
+
+// CountUnlocated counts a request from a client without a GeoIP match,
+// policy being the policy applied to route the client
+func (s *Stats) CountUnlocated(policy string) {
+	s.countChan <- countItem{unlocated: policy, time: time.Now().UTC()}
+}
+
 // Process all stacked download messages
 func (s *Stats) processCountDownload() {
 	s.wg.Add(1)
@@ -100,9 +172,28 @@ func (s *Stats) processCountDownload() {
 			return
 		case c := <-s.countChan:
 			date := c.time.Format("2006_01_02|") // Includes separator
+			if c.unlocated != "" {
+				s.mapStats["u"+date+c.unlocated]++
+				continue
+			}
 			s.mapStats["f"+date+c.filepath]++
+			if c.local {
+				s.mapStats["l"+date+c.filepath]++
+				s.mapStats["L"+date+c.filepath] += c.size
+				continue
+			}
+			if c.fallback != "" {
+				s.mapStats["F"+date+c.fallback]++
+				continue
+			}
 			s.mapStats["m"+date+strconv.Itoa(c.mirrorID)]++
 			s.mapStats["s"+date+strconv.Itoa(c.mirrorID)] += c.size
+			bucket := strconv.FormatInt(bandwidthBucketOf(c.time), 10)
+			s.mapStats["b"+bucket+"|"+strconv.Itoa(c.mirrorID)] += c.size
+			for _, scope := range c.scopes {
+				s.mapStats["r"+bucket+"|"+scope+"|"+strconv.Itoa(c.mirrorID)]++
+				s.mapStats["r"+bucket+"|"+scope+"|"+shareTotal]++
+			}
 		case <-pushTicker.C:
 			s.pushStats()
 		}
@@ -173,6 +264,54 @@ func (s *Stats) pushStats() {
 				rconn.Send("HINCRBY", mkey, object, v)
 				mkey = mkey[:strings.LastIndex(mkey, "_")]
 			}
+		} else if typ == "F" {
+			// Fallback
+
+			fkey := fmt.Sprintf("STATS_FALLBACK_%s", date)
+
+			for i := 0; i < 4; i++ {
+				rconn.Send("HINCRBY", fkey, object, v)
+				fkey = fkey[:strings.LastIndex(fkey, "_")]
+			}
+		} else if typ == "l" {
+			// Local
+
+			lkey := fmt.Sprintf("STATS_LOCAL_%s", date)
+
+			for i := 0; i < 4; i++ {
+				rconn.Send("HINCRBY", lkey, object, v)
+				lkey = lkey[:strings.LastIndex(lkey, "_")]
+			}
+		} else if typ == "L" {
+			// Local bytes
+
+			lkey := fmt.Sprintf("STATS_LOCAL_BYTES_%s", date)
+
+			for i := 0; i < 4; i++ {
+				rconn.Send("HINCRBY", lkey, object, v)
+				lkey = lkey[:strings.LastIndex(lkey, "_")]
+			}
+		} else if typ == "u" {
+			// Unlocated clients
+
+			ukey := fmt.Sprintf("STATS_UNLOCATED_%s", date)
+
+			for i := 0; i < 4; i++ {
+				rconn.Send("HINCRBY", ukey, object, v)
+				ukey = ukey[:strings.LastIndex(ukey, "_")]
+			}
+		} else if typ == "r" {
+			// Requests by scope (see shares.go)
+
+			rkey := fmt.Sprintf("STATS_SHARES_%s", date)
+			rconn.Send("HINCRBY", rkey, object, v)
+			rconn.Send("EXPIRE", rkey, (bandwidthWindow()+1)*bandwidthBucket)
+		} else if typ == "b" {
+			// Bandwidth (see bandwidth.go)
+
+			bkey := fmt.Sprintf("STATS_BANDWIDTH_%s", date)
+			rconn.Send("HINCRBY", bkey, object, v)
+			rconn.Send("EXPIRE", bkey, (bandwidthWindow()+1)*bandwidthBucket)
 		} else {
 			log.Warning("Stats: unknown type", typ)
 		}
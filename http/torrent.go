@@ -0,0 +1,218 @@
+// Copyright (c) 2014-2019 Ludovic Fauvet
+// Licensed under the MIT license
+
+package http
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	. "github.com/opensourceways/mirrorbits/config"
+	"github.com/opensourceways/mirrorbits/core"
+	"github.com/opensourceways/mirrorbits/filesystem"
+	"github.com/opensourceways/mirrorbits/mirrors"
+	"github.com/opensourceways/mirrorbits/torrent"
+)
+
+/*
+	Pieces of the torrents, hashed from the local repository:
+	TORRENT_[path]			size, modTime, pieceLength, pieces
+
+	The pieces are hashed again when the size or the modTime
+	of the file no longer match those of the index.
+*/
+
+const (
+	// torrentRetryAfter is the delay suggested to the clients while the pieces are hashed
+	torrentRetryAfter = 60
+	// torrentMaxJobs is the maximum number of files hashed at the same time
+	torrentMaxJobs = 2
+)
+
+var (
+	// errTorrentPending is returned while the pieces of a torrent are being hashed
+	errTorrentPending = errors.New("The torrent is being generated, please retry later")
+)
+
+// torrentJobs holds the paths of the files being hashed
+var torrentJobs = struct {
+	sync.Mutex
+	running map[string]bool
+}{
+	running: make(map[string]bool),
+}
+
+// torrentHandler returns a torrent of the requested file using the mirrors
+// selected for the client as web seeds, i.e. <path>.torrent
+func (h *HTTP) torrentHandler(w http.ResponseWriter, r *http.Request, ctx *Context) {
+	cnf := GetConfig()
+
+	urlPath, err := sanitizePath(ctx.Path())
+	if err != nil {
+		if err == filesystem.ErrOutsideRepo {
+			http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+			return
+		}
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		return
+	}
+
+	files, isDir := filesystem.GetRepoFiles(urlPath)
+	if len(files) == 0 || isDir || !isTorrentable(files[0]) {
+		http.NotFound(w, r)
+		return
+	}
+
+	fileInfo, err := h.cache.GetFileInfo(files[0])
+	if err != nil {
+		log.Errorf("Error while fetching Fileinfo: %s", err.Error())
+		http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+		return
+	}
+
+	pieceLength, pieces, err := h.torrentPieces(fileInfo)
+	if err == errTorrentPending {
+		w.Header().Set("Retry-After", strconv.Itoa(torrentRetryAfter))
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	} else if err != nil {
+		log.Errorf("Unable to generate the torrent of %s: %s", fileInfo.Path, err)
+		http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+		return
+	}
+
+	clientInfo := locateUnlocated(h.geoip.GetRecord(clientIP(r)), cnf)
+	selected := filesystem.NewFileInfo(urlPath)
+	mlist, _, err := h.mirrorSelector(ctx, h.cache, &selected, clientInfo)
+	if _, ok := err.(net.Error); ok || len(mlist) == 0 {
+		mlist = mirrors.SelectFallbacks(clientInfo)
+	}
+	if len(mlist) > cnf.Torrent.WebSeeds {
+		mlist = mlist[:cnf.Torrent.WebSeeds]
+	}
+
+	meta := torrent.MetaInfo{
+		Name:         filepath.Base(fileInfo.Path),
+		Length:       fileInfo.Size,
+		PieceLength:  pieceLength,
+		Pieces:       pieces,
+		Trackers:     cnf.Torrent.Trackers,
+		CreatedBy:    "Mirrorbits/" + core.VERSION,
+		CreationDate: fileInfo.ModTime,
+	}
+	for _, m := range mlist {
+		meta.WebSeeds = append(meta.WebSeeds, m.HttpURL+fileInfo.Path)
+	}
+
+	var buf bytes.Buffer
+	if err = meta.Write(&buf); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "private, no-cache")
+	w.Header().Set("Content-Type", "application/x-bittorrent")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", meta.Name+torrentSuffix))
+	w.Header().Set("Content-Length", strconv.Itoa(buf.Len()))
+	buf.WriteTo(w)
+}
+
+// isTorrentable returns true if torrents are generated for the file, i.e.
+// the ISO images and the files checked on the mirrors
+func isTorrentable(path string) bool {
+	return strings.HasSuffix(path, filesystem.StandardISOFileExtension) || filesystem.IsSelectorFile(path)
+}
+
+// torrentPieces returns the pieces of the file cached in the database. The
+// hashing of the pieces is started in the background if they are missing
+// or outdated, errTorrentPending being returned in the meantime.
+func (h *HTTP) torrentPieces(fileInfo filesystem.FileInfo) (pieceLength int64, pieces []byte, err error) {
+	rconn := h.redis.Get()
+	defer rconn.Close()
+
+	values, err := redis.Values(rconn.Do("HMGET", "TORRENT_"+fileInfo.Path, "size", "modTime", "pieceLength", "pieces"))
+	if err != nil {
+		return 0, nil, err
+	}
+	var size, modTime int64
+	if _, err = redis.Scan(values, &size, &modTime, &pieceLength, &pieces); err != nil {
+		return 0, nil, err
+	}
+	if size == fileInfo.Size && modTime == fileInfo.ModTime.Unix() && len(pieces) > 0 {
+		return pieceLength, pieces, nil
+	}
+
+	h.hashTorrent(fileInfo)
+	return 0, nil, errTorrentPending
+}
+
+// hashTorrent hashes the pieces of the file from the local repository
+// in the background and stores them in the database. Up to torrentMaxJobs
+// files are hashed at the same time, the others being left to the next
+// requests.
+func (h *HTTP) hashTorrent(fileInfo filesystem.FileInfo) {
+	torrentJobs.Lock()
+	defer torrentJobs.Unlock()
+	if torrentJobs.running[fileInfo.Path] || len(torrentJobs.running) >= torrentMaxJobs {
+		return
+	}
+	torrentJobs.running[fileInfo.Path] = true
+
+	go func() {
+		defer func() {
+			torrentJobs.Lock()
+			delete(torrentJobs.running, fileInfo.Path)
+			torrentJobs.Unlock()
+		}()
+
+		start := time.Now()
+		pieceLength := torrent.PieceLength(fileInfo.Size)
+		pieces, err := hashLocalFile(filepath.Join(GetConfig().Repository, fileInfo.Path), fileInfo.Size, pieceLength)
+		if err != nil {
+			log.Errorf("Torrent: unable to hash %s: %s", fileInfo.Path, err)
+			return
+		}
+
+		rconn := h.redis.Get()
+		defer rconn.Close()
+		_, err = rconn.Do("HMSET", "TORRENT_"+fileInfo.Path,
+			"size", fileInfo.Size,
+			"modTime", fileInfo.ModTime.Unix(),
+			"pieceLength", pieceLength,
+			"pieces", pieces)
+		if err != nil {
+			log.Errorf("Torrent: unable to store the pieces of %s: %s", fileInfo.Path, err)
+			return
+		}
+		log.Noticef("Torrent: hashed %s in %s", fileInfo.Path, time.Since(start).Round(time.Second))
+	}()
+}
+
+// hashLocalFile returns the pieces of a file of the local repository,
+// the file being expected to match the size of the index
+func hashLocalFile(path string, size, pieceLength int64) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	stat, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if stat.Size() != size {
+		return nil, fmt.Errorf("size mismatch with the index (%d != %d)", stat.Size(), size)
+	}
+	return torrent.HashPieces(f, pieceLength)
+}
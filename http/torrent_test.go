@@ -0,0 +1,78 @@
+// Copyright (c) 2014-2019 Ludovic Fauvet
+// Licensed under the MIT license
+
+package http
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/opensourceways/mirrorbits/filesystem"
+	"github.com/opensourceways/mirrorbits/torrent"
+)
+
+func TestContext_Torrent(t *testing.T) {
+	ctx := NewContext(httptest.NewRecorder(), httptest.NewRequest("GET", "/a/b.iso.torrent", nil), Templates{})
+	if ctx.Type() != TORRENT || !ctx.IsTorrent() {
+		t.Fatalf("Expected a torrent request")
+	}
+	if ctx.Path() != "/a/b.iso" {
+		t.Fatalf("Expected /a/b.iso, got %s", ctx.Path())
+	}
+}
+
+func TestHashLocalFile(t *testing.T) {
+	data := bytes.Repeat([]byte("mirrorbits"), 100)
+	path := filepath.Join(t.TempDir(), "a.iso")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatalf("Unable to write the file: %s", err)
+	}
+
+	pieces, err := hashLocalFile(path, int64(len(data)), 256)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	expected, _ := torrent.HashPieces(bytes.NewReader(data), 256)
+	if !bytes.Equal(pieces, expected) || len(pieces) != 4*20 {
+		t.Fatalf("Unexpected pieces")
+	}
+
+	if _, err = hashLocalFile(path, 10, 256); err == nil {
+		t.Fatalf("Expected a size mismatch")
+	}
+}
+
+func TestIsTorrentable(t *testing.T) {
+	if !isTorrentable("openEuler-24.03-LTS/ISO/x86_64/a.iso") {
+		t.Fatalf("Expected the ISO images to be torrentable")
+	}
+	if isTorrentable("openEuler-24.03-LTS/OS/x86_64/Packages/a.rpm") {
+		t.Fatalf("Expected the files not checked on the mirrors to be refused")
+	}
+}
+
+func TestHashTorrent_MaxJobs(t *testing.T) {
+	torrentJobs.Lock()
+	for i := 0; i < torrentMaxJobs; i++ {
+		torrentJobs.running[strconv.Itoa(i)] = true
+	}
+	torrentJobs.Unlock()
+	defer func() {
+		torrentJobs.Lock()
+		torrentJobs.running = make(map[string]bool)
+		torrentJobs.Unlock()
+	}()
+
+	h := &HTTP{}
+	h.hashTorrent(filesystem.FileInfo{Path: "a.iso", Size: 1024})
+
+	torrentJobs.Lock()
+	defer torrentJobs.Unlock()
+	if torrentJobs.running["a.iso"] || len(torrentJobs.running) != torrentMaxJobs {
+		t.Fatalf("Expected no more than %d jobs, got %d", torrentMaxJobs, len(torrentJobs.running))
+	}
+}
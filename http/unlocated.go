@@ -0,0 +1,90 @@
+// Copyright (c) 2014-2019 Ludovic Fauvet
+// Licensed under the MIT license
+
+package http
+
+import (
+	"math"
+	"strings"
+
+	. "github.com/opensourceways/mirrorbits/config"
+	"github.com/opensourceways/mirrorbits/mirrors"
+	"github.com/opensourceways/mirrorbits/network"
+)
+
+const unlocatedReason = "Unlocated client"
+
+// unlocatedPolicy returns the policy applied to the clients without a GeoIP match
+func unlocatedPolicy(cnf *Configuration) string {
+	if cnf.Unlocated.Policy == "" {
+		return "random"
+	}
+	return cnf.Unlocated.Policy
+}
+
+// locateUnlocated gives the default region to a client without a GeoIP
+// match when the region policy is used, the record is returned untouched
+// otherwise.
+func locateUnlocated(clientInfo network.GeoIPRecord, cnf *Configuration) network.GeoIPRecord {
+	if clientInfo.IsValid() || unlocatedPolicy(cnf) != "region" {
+		return clientInfo
+	}
+	region := cnf.Unlocated.Region
+	return network.GeoIPRecord{
+		IP:            clientInfo.IP,
+		ASNum:         clientInfo.ASNum,
+		ASName:        clientInfo.ASName,
+		ASNGroup:      clientInfo.ASNGroup,
+		CountryCode:   region.CountryCode,
+		ContinentCode: region.ContinentCode,
+		Latitude:      region.Latitude,
+		Longitude:     region.Longitude,
+	}
+}
+
+// selectUnlocated orders the mirrors for a client without a GeoIP match
+// according to the configured policy. The mirrors matching a network rule
+// of the client are always kept.
+func (h DefaultEngine) selectUnlocated(ctx *Context, mlist, excluded mirrors.Mirrors, cnf *Configuration) (mirrors.Mirrors, mirrors.Mirrors) {
+	switch unlocatedPolicy(cnf) {
+	case "fallbacks":
+		// Let the caller use the fallbacks
+		mlist, excluded = keepUnlocated(mlist, excluded, func(m *mirrors.Mirror) bool {
+			return false
+		})
+	case "mirrors":
+		mlist, excluded = keepUnlocated(mlist, excluded, func(m *mirrors.Mirror) bool {
+			for _, name := range cnf.Unlocated.Mirrors {
+				if strings.EqualFold(name, m.Name) {
+					return true
+				}
+			}
+			return false
+		})
+	case "weighted":
+		return h.weightedDistribution(ctx, mlist, math.MaxFloat32), excluded
+	}
+
+	// Shuffle the list
+	for i := range mlist {
+		j := int(h.int63n(int64(i + 1)))
+		mlist[i], mlist[j] = mlist[j], mlist[i]
+	}
+	return mlist, excluded
+}
+
+// keepUnlocated only keeps the mirrors for which keep returns true along
+// with the mirrors matching a network rule, the others being excluded.
+// The fallbacks are used by the caller if no mirror is kept.
+func keepUnlocated(mlist, excluded mirrors.Mirrors, keep func(*mirrors.Mirror) bool) (mirrors.Mirrors, mirrors.Mirrors) {
+	var kept, rejected mirrors.Mirrors
+	for _, m := range mlist {
+		if m.NetworkMatch != "" || keep(&m) {
+			kept = append(kept, m)
+		} else {
+			m.ExcludeReason = unlocatedReason
+			rejected = append(rejected, m)
+		}
+	}
+	return kept, append(excluded, rejected...)
+}
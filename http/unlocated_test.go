@@ -0,0 +1,100 @@
+// Copyright (c) 2014-2019 Ludovic Fauvet
+// Licensed under the MIT license
+
+package http
+
+import (
+	"net"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/opensourceways/mirrorbits/config"
+	"github.com/opensourceways/mirrorbits/filesystem"
+	"github.com/opensourceways/mirrorbits/network"
+)
+
+func TestLocateUnlocated(t *testing.T) {
+	cnf := &Configuration{
+		Unlocated: Unlocated{
+			Policy: "region",
+			Region: UnlocatedRegion{CountryCode: "CN", ContinentCode: "AS", Latitude: 39.9, Longitude: 116.4},
+		},
+	}
+
+	clientInfo := locateUnlocated(network.GeoIPRecord{IP: net.ParseIP("10.0.0.1"), ASNum: 1234, ASNGroup: "telecom"}, cnf)
+	if !clientInfo.IsValid() || clientInfo.CountryCode != "CN" || clientInfo.Latitude != 39.9 {
+		t.Fatalf("Expected the client to be located in the default region, got %+v", clientInfo)
+	}
+	if clientInfo.ASNum != 1234 || !clientInfo.IP.Equal(net.ParseIP("10.0.0.1")) {
+		t.Fatalf("The address and the AS of the client must be kept")
+	}
+	if clientInfo.ASNGroup != "telecom" {
+		t.Fatalf("The ASN group of the client must be kept, got %q", clientInfo.ASNGroup)
+	}
+
+	located := network.GeoIPRecord{CountryCode: "FR", ContinentCode: "EU"}
+	if clientInfo := locateUnlocated(located, cnf); clientInfo.CountryCode != "FR" {
+		t.Fatalf("Located clients must be left untouched, got %s", clientInfo.CountryCode)
+	}
+
+	cnf.Unlocated.Policy = "random"
+	if clientInfo := locateUnlocated(network.GeoIPRecord{}, cnf); clientInfo.IsValid() {
+		t.Fatalf("The default region must only be used by the region policy")
+	}
+}
+
+func TestDefaultEngine_UnlocatedPolicies(t *testing.T) {
+	cnf := &Configuration{
+		WeightDistributionRange: 1.5,
+		SelectionMode:           "score",
+	}
+	fileInfo := &filesystem.FileInfo{Path: "/openEuler-22.03-LTS"}
+	ctx := NewContext(httptest.NewRecorder(), httptest.NewRequest("GET", "/openEuler-22.03-LTS", nil), Templates{})
+	clientInfo := network.GeoIPRecord{}
+
+	engine := DefaultEngine{}
+	engine.Seed(42)
+
+	// Random
+	mlist, excluded, _ := engine.Selection(ctx, fileInfo, clientInfo, selectionTestMirrors(), cnf)
+	if len(mlist) != 3 || len(excluded) != 0 {
+		t.Fatalf("Expected 3 mirrors, got %d", len(mlist))
+	}
+
+	// Mirrors
+	cnf.Unlocated = Unlocated{Policy: "mirrors", Mirrors: []string{"Near-Big", "unknown"}}
+	mlist, excluded, _ = engine.Selection(ctx, fileInfo, clientInfo, selectionTestMirrors(), cnf)
+	if len(mlist) != 1 || mlist[0].Name != "near-big" {
+		t.Fatalf("Expected near-big only, got %d mirrors", len(mlist))
+	}
+	if len(excluded) != 2 || excluded[0].ExcludeReason != unlocatedReason {
+		t.Fatalf("Expected the other mirrors to be excluded")
+	}
+
+	// Fallbacks
+	cnf.Unlocated = Unlocated{Policy: "fallbacks"}
+	mlist, excluded, _ = engine.Selection(ctx, fileInfo, clientInfo, selectionTestMirrors(), cnf)
+	if len(mlist) != 0 || len(excluded) != 3 {
+		t.Fatalf("Expected all the mirrors to be excluded, got %d mirrors", len(mlist))
+	}
+
+	// Mirrors matching a network rule are always kept
+	mirrorsWithMatch := selectionTestMirrors()
+	mirrorsWithMatch[2].NetworkMatch = "10.0.0.0/8"
+	mlist, _ = engine.selectUnlocated(ctx, mirrorsWithMatch, nil, cnf)
+	if len(mlist) != 1 || mlist[0].Name != "far" {
+		t.Fatalf("Expected the mirror matching the network to be kept")
+	}
+
+	// Weighted
+	cnf.Unlocated = Unlocated{Policy: "weighted"}
+	first := map[string]int{}
+	for i := 0; i < 1000; i++ {
+		mlist, _, _ = engine.Selection(ctx, fileInfo, clientInfo, selectionTestMirrors(), cnf)
+		first[mlist[0].Name]++
+	}
+	// far has a weight of 100000 against 100 and 300 for the others
+	if first["far"] < 950 {
+		t.Fatalf("Unexpected distribution: %v", first)
+	}
+}
@@ -176,9 +176,13 @@ func LogDownload(typ string, statuscode int, p *mirrors.Results, err error) {
 		if m.Asnum > 0 && m.Asnum == p.ClientInfo.ASNum {
 			sameASNum = "same"
 		}
+		asnGroup := ""
+		if m.ASNGroup != "" && m.ASNGroup == p.ClientInfo.ASNGroup {
+			asnGroup = " asngroup:" + m.ASNGroup
+		}
 
-		dlogger.l.Printf("%s %d \"%s\" ip:%s mirror:%s%s %sasn:%d distance:%skm countries:%s",
-			typ, statuscode, p.FileInfo.Path, p.IP, m.Name, fallback, sameASNum, m.Asnum, distance, countries)
+		dlogger.l.Printf("%s %d \"%s\" ip:%s mirror:%s%s %sasn:%d%s distance:%skm countries:%s",
+			typ, statuscode, p.FileInfo.Path, p.IP, m.Name, fallback, sameASNum, m.Asnum, asnGroup, distance, countries)
 	} else if statuscode == 404 && p != nil {
 		dlogger.l.Printf("%s 404 \"%s\" ip:%s", typ, p.FileInfo.Path, p.IP)
 	} else if statuscode == 500 && p != nil {
@@ -21,6 +21,7 @@ import (
 	"github.com/opensourceways/mirrorbits/http"
 	"github.com/opensourceways/mirrorbits/logs"
 	"github.com/opensourceways/mirrorbits/mirrors"
+	"github.com/opensourceways/mirrorbits/notify"
 	"github.com/opensourceways/mirrorbits/process"
 	"github.com/opensourceways/mirrorbits/rpc"
 	"github.com/pkg/errors"
@@ -65,6 +66,10 @@ func main() {
 		c := mirrors.NewCache(r)
 		rpcs.SetCache(c)
 		h := http.HTTPServer(r, c)
+		rpcs.SetExplainer(h)
+
+		/* Start the notifications */
+		n := notify.NewNotifier(r)
 
 		/* Start the background monitor */
 		m := daemon.NewMonitor(r, c)
@@ -159,6 +164,8 @@ func main() {
 		log.Debug("Terminating server")
 		h.Terminate()
 
+		n.Stop()
+
 		r.Close()
 
 		process.RemovePidFile()
@@ -0,0 +1,87 @@
+// Copyright (c) 2014-2019 Ludovic Fauvet
+// Licensed under the MIT license
+
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// DefaultBuckets are the upper bounds in seconds of the latency histograms
+var DefaultBuckets = []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5}
+
+// registry holds the metrics exposed by mirrorbits
+var registry = prometheus.NewRegistry()
+
+// NewCounter registers a counter partitioned by the given labels
+func NewCounter(name, help string, labels ...string) *prometheus.CounterVec {
+	c := prometheus.NewCounterVec(prometheus.CounterOpts{Name: name, Help: help}, labels)
+	registry.MustRegister(c)
+	return c
+}
+
+// NewGauge registers a gauge partitioned by the given labels
+func NewGauge(name, help string, labels ...string) *prometheus.GaugeVec {
+	g := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: name, Help: help}, labels)
+	registry.MustRegister(g)
+	return g
+}
+
+// NewHistogram registers a histogram with the given upper bounds
+// partitioned by the given labels
+func NewHistogram(name, help string, buckets []float64, labels ...string) *prometheus.HistogramVec {
+	h := prometheus.NewHistogramVec(prometheus.HistogramOpts{Name: name, Help: help, Buckets: buckets}, labels)
+	registry.MustRegister(h)
+	return h
+}
+
+// Emit reports a sample of a collected metric
+type Emit func(v float64, values ...string)
+
+// collector is a metric whose samples are read at scrape time
+type collector struct {
+	desc      *prometheus.Desc
+	valueType prometheus.ValueType
+	collect   func(emit Emit)
+}
+
+func (c *collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *collector) Collect(ch chan<- prometheus.Metric) {
+	c.collect(func(v float64, values ...string) {
+		ch <- prometheus.MustNewConstMetric(c.desc, c.valueType, v, values...)
+	})
+}
+
+// NewGaugeFunc registers a gauge whose samples are reported by collect at
+// scrape time. An error is returned if the name is already registered.
+func NewGaugeFunc(name, help string, labels []string, collect func(emit Emit)) error {
+	return registry.Register(&collector{
+		desc:      prometheus.NewDesc(name, help, labels, nil),
+		valueType: prometheus.GaugeValue,
+		collect:   collect,
+	})
+}
+
+// NewCounterFunc is the counter counterpart of NewGaugeFunc
+func NewCounterFunc(name, help string, labels []string, collect func(emit Emit)) error {
+	return registry.Register(&collector{
+		desc:      prometheus.NewDesc(name, help, labels, nil),
+		valueType: prometheus.CounterValue,
+		collect:   collect,
+	})
+}
+
+// Handler returns the HTTP handler exposing the metrics
+func Handler() http.Handler {
+	handler := promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "no-cache")
+		handler.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,66 @@
+// Copyright (c) 2014-2019 Ludovic Fauvet
+// Licensed under the MIT license
+
+package metrics
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// scrape returns the exposition of the registered metrics
+func scrape(t *testing.T) string {
+	rec := httptest.NewRecorder()
+	Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Fatalf("Unexpected Content-Type %q", ct)
+	}
+	if cc := rec.Header().Get("Cache-Control"); cc != "no-cache" {
+		t.Fatalf("Unexpected Cache-Control %q", cc)
+	}
+	return rec.Body.String()
+}
+
+func TestHandler(t *testing.T) {
+	c := NewCounter("test_requests_total", "Requests.", "mirror", "country")
+	c.WithLabelValues("m1", "FR").Inc()
+	c.WithLabelValues("m1", "FR").Add(2)
+
+	h := NewHistogram("test_latency_seconds", "Latency.", []float64{0.1, 1})
+	h.WithLabelValues().Observe(0.05)
+	h.WithLabelValues().Observe(5)
+
+	err := NewGaugeFunc("test_up", "Up.", []string{"mirror"}, func(emit Emit) {
+		emit(1, "m1")
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	out := scrape(t)
+	for _, expected := range []string{
+		"# TYPE test_requests_total counter\n",
+		"test_requests_total{country=\"FR\",mirror=\"m1\"} 3\n",
+		"test_latency_seconds_bucket{le=\"1\"} 1\n",
+		"test_latency_seconds_count 2\n",
+		"# TYPE test_up gauge\ntest_up{mirror=\"m1\"} 1\n",
+	} {
+		if !strings.Contains(out, expected) {
+			t.Fatalf("Missing %q in:\n%s", expected, out)
+		}
+	}
+}
+
+func TestGaugeFuncDuplicate(t *testing.T) {
+	if err := NewGaugeFunc("test_duplicate", "Duplicate.", nil, func(emit Emit) { emit(1) }); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if err := NewGaugeFunc("test_duplicate", "Duplicate.", nil, func(emit Emit) { emit(2) }); err == nil {
+		t.Fatalf("The duplicate registration must be rejected")
+	}
+
+	if out := scrape(t); !strings.Contains(out, "\ntest_duplicate 1\n") {
+		t.Fatalf("The first collector must be kept:\n%s", out)
+	}
+}
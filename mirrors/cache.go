@@ -5,8 +5,11 @@ package mirrors
 
 import (
 	"fmt"
+	"hash/fnv"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 	"unsafe"
 
@@ -25,6 +28,15 @@ type Cache struct {
 	fmCache  *LRUCache
 	mCache   *LRUCache
 	fimCache *LRUCache
+	dmCache  *LRUCache
+
+	// mirrorsVersion is a digest of the state of the mirrors, computed
+	// again on use once mirrorsDirty, mirrorsModTime being the time it
+	// last changed
+	mirrorsLock    sync.Mutex
+	mirrorsVersion uint64
+	mirrorsModTime time.Time
+	mirrorsDirty   bool
 
 	mirrorUpdateEvent      chan string
 	fileUpdateEvent        chan string
@@ -66,12 +78,14 @@ func NewCache(r *database.Redis) *Cache {
 	c := &Cache{
 		r: r,
 	}
+	c.touchMirrors()
 
 	// Create the LRU
 	c.fiCache = NewLRUCache(1024000)
 	c.fmCache = NewLRUCache(2048000)
 	c.mCache = NewLRUCache(1024000)
 	c.fimCache = NewLRUCache(4096000)
+	c.dmCache = NewLRUCache(1024000)
 
 	// Create event channels
 	c.mirrorUpdateEvent = make(chan string, 10)
@@ -93,6 +107,7 @@ func NewCache(r *database.Redis) *Cache {
 			select {
 			case data := <-c.mirrorUpdateEvent:
 				c.mCache.Delete(data)
+				c.touchMirrors()
 				select {
 				case c.invalidationEvent <- data:
 				default:
@@ -100,12 +115,15 @@ func NewCache(r *database.Redis) *Cache {
 				}
 			case data := <-c.fileUpdateEvent:
 				c.fiCache.Delete(data)
+				c.deleteDirMirrors(data)
 			case data := <-c.mirrorFileUpdateEvent:
 				s := strings.SplitN(data, " ", 2)
 				c.fmCache.Delete(s[1])
 				c.fimCache.Delete(fmt.Sprintf("%s|%s", s[0], s[1]))
+				c.deleteDirMirrors(s[1])
 			case <-c.pubsubReconnectedEvent:
 				c.Clear()
+				c.touchMirrors()
 			}
 		}
 	}()
@@ -119,6 +137,97 @@ func (c *Cache) Clear() {
 	c.fmCache.Clear()
 	c.mCache.Clear()
 	c.fimCache.Clear()
+	c.dmCache.Clear()
+}
+
+// touchMirrors records a change of the state of the mirrors
+func (c *Cache) touchMirrors() {
+	c.mirrorsLock.Lock()
+	defer c.mirrorsLock.Unlock()
+	c.mirrorsDirty = true
+}
+
+// MirrorsVersion returns the version of the state of the mirrors, changing
+// whenever a mirror is updated, and the time it last changed. The version
+// is a digest of the mirrors stored in the database so all the instances
+// agree on it, 0 if it could not be computed yet.
+func (c *Cache) MirrorsVersion() (uint64, time.Time) {
+	c.mirrorsLock.Lock()
+	defer c.mirrorsLock.Unlock()
+	if c.mirrorsDirty {
+		version, err := c.mirrorsDigest()
+		if err != nil {
+			log.Errorf("Unable to compute the version of the mirrors: %s", err)
+			return c.mirrorsVersion, c.mirrorsModTime
+		}
+		if version != c.mirrorsVersion {
+			c.mirrorsVersion = version
+			c.mirrorsModTime = time.Now()
+		}
+		c.mirrorsDirty = false
+	}
+	return c.mirrorsVersion, c.mirrorsModTime
+}
+
+// mirrorsDigest returns a digest of the mirrors stored in the database,
+// independent of the order of their fields
+func (c *Cache) mirrorsDigest() (uint64, error) {
+	rconn := c.r.Get()
+	defer rconn.Close()
+
+	ids, err := redis.Strings(rconn.Do("HKEYS", "MIRRORS"))
+	if err != nil {
+		return 0, err
+	}
+
+	var digest uint64
+	h := fnv.New64a()
+	for _, id := range ids {
+		fields, err := redis.StringMap(rconn.Do("HGETALL", "MIRROR_"+id))
+		if err != nil {
+			return 0, err
+		}
+		for field, value := range fields {
+			h.Reset()
+			fmt.Fprintf(h, "%s\x00%s\x00%s", id, field, value)
+			digest ^= h.Sum64()
+		}
+	}
+	h.Reset()
+	fmt.Fprintf(h, "%d", len(ids))
+	return digest ^ h.Sum64(), nil
+}
+
+// LRUStats holds the statistics of one of the LRUs of the cache
+type LRUStats struct {
+	Name     string
+	Length   uint64
+	Size     uint64
+	Capacity uint64
+	Hits     uint64
+	Misses   uint64
+}
+
+// Stats returns the statistics of the LRUs of the cache
+func (c *Cache) Stats() []LRUStats {
+	lrus := []struct {
+		name string
+		lru  *LRUCache
+	}{
+		{"fileinfo", c.fiCache},
+		{"filemirrors", c.fmCache},
+		{"mirror", c.mCache},
+		{"fileinfomirror", c.fimCache},
+		{"dirmirrors", c.dmCache},
+	}
+	stats := make([]LRUStats, 0, len(lrus))
+	for _, l := range lrus {
+		s := LRUStats{Name: l.name}
+		s.Length, s.Size, s.Capacity, _ = l.lru.Stats()
+		s.Hits, s.Misses = l.lru.Lookups()
+		stats = append(stats, s)
+	}
+	return stats
 }
 
 // GetMirrorInvalidationEvent returns a channel that contains ID of mirrors
@@ -152,7 +261,7 @@ func (c *Cache) fetchFileInfo(path string) (f filesystem.FileInfo, err error) {
 	}
 
 	f.Size, _ = strconv.ParseInt(reply[0], 10, 64)
-	f.ModTime, _ = time.Parse(time.RFC1123, reply[1])
+	f.ModTime, _ = time.Parse("2006-01-02 15:04:05.999999999 -0700 MST", reply[1])
 	f.Sha256 = reply[2]
 	c.fiCache.Set(path, &fileInfoValue{value: f})
 	return
@@ -161,61 +270,121 @@ func (c *Cache) fetchFileInfo(path string) (f filesystem.FileInfo, err error) {
 // GetMirrors returns all the mirrors serving a given file either from the cache
 // or directly from the database if the object is not yet stored in the cache.
 func (c *Cache) GetMirrors(path string, clientInfo network.GeoIPRecord) (mirrors []Mirror, err error) {
-	var mirrorsIDs []int
-	v, ok := c.fmCache.Get(path)
-	if ok {
-		mirrorsIDs = v.(*fileMirrorValue).value
-	}
-
-	if len(mirrorsIDs) == 0 {
-		mirrorsIDs, err = c.fetchFileMirrors(path)
-		if err != nil {
-			return
-		}
+	mirrorsIDs, err := c.getFileMirrors(path)
+	if err != nil {
+		return
 	}
 	mirrors = make([]Mirror, 0, len(mirrorsIDs))
 	for _, id := range mirrorsIDs {
 		var mirror Mirror
 		var fileInfo filesystem.FileInfo
-		v, ok := c.mCache.Get(strconv.Itoa(id))
-		if ok {
-			mirror = v.(*mirrorValue).value
-		} else {
-			//TODO execute missing items in a MULTI query
-			mirror, err = c.fetchMirror(id)
-			if err != nil {
-				return
-			}
+		mirror, err = c.GetMirror(id)
+		if err != nil {
+			return
 		}
-		v, ok = c.fimCache.Get(fmt.Sprintf("%d|%s", id, path))
-		if ok {
-			fileInfo = v.(*fileInfoValue).value
-		} else {
-			fileInfo, err = c.fetchFileInfoMirror(id, path)
-			if err != nil {
-				return
-			}
+		fileInfo, err = c.GetFileInfoMirror(id, path)
+		if err != nil {
+			return
 		}
 		if fileInfo.Size >= 0 {
 			mirror.FileInfo = &fileInfo
+
+			// Add the path in the results so we can access it from the templates
+			mirror.FileInfo.Path = path
 		}
 
-		// Add the path in the results so we can access it from the templates
-		mirror.FileInfo.Path = path
+		mirror.setDistance(clientInfo)
+		mirrors = append(mirrors, mirror)
+	}
+	return
+}
+
+// GetDirMirrors returns the mirrors serving every file of the given list, i.e.
+// the indexed content of the directory dir, either from the cache or directly
+// from the database.
+func (c *Cache) GetDirMirrors(dir string, files []string, clientInfo network.GeoIPRecord) (mirrors []Mirror, err error) {
+	if len(files) == 0 {
+		return
+	}
+
+	mirrorsIDs, err := c.getDirMirrors(dir, files)
+	if err != nil {
+		return
+	}
 
-		if clientInfo.IsValid() {
-			mirror.Distance = utils.GetDistanceKm(clientInfo.Latitude,
-				clientInfo.Longitude,
-				mirror.Latitude,
-				mirror.Longitude)
-		} else {
-			mirror.Distance = 0
+	mirrors = make([]Mirror, 0, len(mirrorsIDs))
+	for _, id := range mirrorsIDs {
+		var mirror Mirror
+		mirror, err = c.GetMirror(id)
+		if err != nil {
+			return
 		}
+		mirror.setDistance(clientInfo)
 		mirrors = append(mirrors, mirror)
 	}
 	return
 }
 
+// setDistance computes the distance between the mirror and the client
+func (m *Mirror) setDistance(clientInfo network.GeoIPRecord) {
+	if clientInfo.IsValid() {
+		m.Distance = utils.GetDistanceKm(clientInfo.Latitude,
+			clientInfo.Longitude,
+			m.Latitude,
+			m.Longitude)
+	} else {
+		m.Distance = 0
+	}
+}
+
+// getDirMirrors returns the IDs of the mirrors serving every file of a directory
+func (c *Cache) getDirMirrors(dir string, files []string) (ids []int, err error) {
+	if v, ok := c.dmCache.Get(dir); ok {
+		return v.(*fileMirrorValue).value, nil
+	}
+
+	counts := make(map[int]int)
+	for _, path := range files {
+		var fileIDs []int
+		fileIDs, err = c.getFileMirrors(path)
+		if err != nil {
+			return
+		}
+		for _, id := range fileIDs {
+			counts[id]++
+		}
+	}
+
+	ids = make([]int, 0, len(counts))
+	for id, count := range counts {
+		if count == len(files) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+	c.dmCache.Set(dir, &fileMirrorValue{value: ids})
+	return
+}
+
+// deleteDirMirrors invalidates the mirrors of the directories containing the given path
+func (c *Cache) deleteDirMirrors(path string) {
+	for i := strings.LastIndex(path, "/"); i > 0; i = strings.LastIndex(path[:i], "/") {
+		c.dmCache.Delete(path[:i])
+	}
+}
+
+// getFileMirrors returns the IDs of the mirrors serving a given file
+func (c *Cache) getFileMirrors(path string) (ids []int, err error) {
+	v, ok := c.fmCache.Get(path)
+	if ok {
+		ids = v.(*fileMirrorValue).value
+	}
+	if len(ids) == 0 {
+		ids, err = c.fetchFileMirrors(path)
+	}
+	return
+}
+
 func (c *Cache) fetchFileMirrors(path string) (ids []int, err error) {
 	rconn := c.r.Get()
 	defer rconn.Close()
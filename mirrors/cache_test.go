@@ -469,3 +469,105 @@ func TestCache_GetMirrors(t *testing.T) {
 		t.Fatalf("Distance between user and m2 is wrong, got %d, expected 334", int(mirrors[1].Distance))
 	}
 }
+
+func TestCache_GetDirMirrors(t *testing.T) {
+	mock, conn := PrepareRedisTest()
+	conn.ConnectPubsub()
+
+	c := NewCache(conn)
+
+	clientInfo := network.GeoIPRecord{
+		CountryCode: "FR",
+		Latitude:    48.8567,
+		Longitude:   2.3508,
+	}
+
+	mock.Command("SMEMBERS", "FILEMIRRORS_test/a.iso").Expect([]interface{}{
+		[]byte("1"),
+		[]byte("2"),
+	})
+	mock.Command("SMEMBERS", "FILEMIRRORS_test/a.iso.sha256sum").Expect([]interface{}{
+		[]byte("2"),
+		[]byte("3"),
+		[]byte("1"),
+	})
+	mock.Command("HGETALL", "MIRROR_1").ExpectMap(map[string]string{
+		"ID":        "1",
+		"latitude":  "52.5167",
+		"longitude": "13.3833",
+	})
+	mock.Command("HGETALL", "MIRROR_2").ExpectMap(map[string]string{
+		"ID":        "2",
+		"latitude":  "51.5072",
+		"longitude": "0.1275",
+	})
+	cmdGetMirrorM3 := mock.Command("HGETALL", "MIRROR_3").ExpectMap(map[string]string{
+		"ID": "3",
+	})
+
+	files := []string{"test/a.iso", "test/a.iso.sha256sum"}
+	mirrors, err := c.GetDirMirrors("test", files, clientInfo)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+
+	if mock.Stats(cmdGetMirrorM3) > 0 {
+		t.Fatalf("Mirrors missing a file must not be fetched")
+	}
+	if len(mirrors) != 2 || mirrors[0].ID != 1 || mirrors[1].ID != 2 {
+		t.Fatalf("Expected mirrors 1 and 2, got %v", mirrors)
+	}
+	if mirrors[0].FileInfo != nil {
+		t.Fatalf("Directory results must not carry file information")
+	}
+	if int(mirrors[1].Distance) != int(334) {
+		t.Fatalf("Distance between user and m2 is wrong, got %d, expected 334", int(mirrors[1].Distance))
+	}
+
+	cmdGetFileMirrors := mock.Command("SMEMBERS", "FILEMIRRORS_test/a.iso").Expect([]interface{}{
+		[]byte("1"),
+	})
+	calls := mock.Stats(cmdGetFileMirrors)
+	if _, err = c.GetDirMirrors("test", files, clientInfo); err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+	if mock.Stats(cmdGetFileMirrors) != calls {
+		t.Fatalf("The mirrors of the directory must be cached")
+	}
+
+	c.deleteDirMirrors("test/a.iso")
+	c.fmCache.Delete("test/a.iso")
+	mirrors, err = c.GetDirMirrors("test", files, clientInfo)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+	if len(mirrors) != 1 || mirrors[0].ID != 1 {
+		t.Fatalf("Expected mirror 1 once the directory is invalidated, got %v", mirrors)
+	}
+}
+
+func TestCache_MirrorsVersion(t *testing.T) {
+	m, r := PrepareMiniredisTest(t)
+	m.HSet("MIRRORS", "1", "m1")
+	m.HSet("MIRROR_1", "ID", "1", "name", "m1", "up", "1")
+
+	// Two instances sharing the database agree on the version
+	c1 := &Cache{r: r}
+	c2 := &Cache{r: r}
+	c1.touchMirrors()
+	c2.touchMirrors()
+	v1, _ := c1.MirrorsVersion()
+	v2, _ := c2.MirrorsVersion()
+	if v1 == 0 || v1 != v2 {
+		t.Fatalf("Expected the same version, got %x and %x", v1, v2)
+	}
+
+	m.HSet("MIRROR_1", "up", "0")
+	if v, _ := c1.MirrorsVersion(); v != v1 {
+		t.Fatalf("The version must only change once the mirrors are touched")
+	}
+	c1.touchMirrors()
+	if v, _ := c1.MirrorsVersion(); v == v1 {
+		t.Fatalf("Expected a new version once the mirror changed")
+	}
+}
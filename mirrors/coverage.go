@@ -0,0 +1,137 @@
+// Copyright (c) 2014-2019 Ludovic Fauvet
+// Licensed under the MIT license
+
+package mirrors
+
+import (
+	"strings"
+	"time"
+
+	"github.com/opensourceways/mirrorbits/filesystem"
+)
+
+const (
+	// CoveragePresent, CoverageMissing and CoverageStale are the states of
+	// the scenarios and architectures of the versions on the mirrors
+	CoveragePresent = "present"
+	CoverageMissing = "missing"
+	CoverageStale   = "stale"
+)
+
+// CoverageColumn is a scenario and an architecture of a version
+type CoverageColumn struct {
+	Version  string
+	Scenario string
+	Arch     string
+}
+
+// CoverageCell is the state of a column on a mirror, LastSeen being
+// the last successful scan of the mirror if it carries the files
+type CoverageCell struct {
+	State    string
+	LastSeen *time.Time `json:",omitempty"`
+}
+
+// CoverageRow holds the states of the columns on a mirror
+type CoverageRow struct {
+	Name         string
+	HttpURL      string
+	Country      string
+	CountryCodes []string
+	Enabled      bool
+	Up           bool
+	Cells        []CoverageCell
+}
+
+// CoverageMatrix holds the states of the versions on the public mirrors
+type CoverageMatrix struct {
+	Columns []CoverageColumn
+	Rows    []CoverageRow
+}
+
+// Coverage computes the states of the targets on the given public mirrors
+// from the files found by their scans, the index of a mirror not scanned
+// successfully since staleAfter being outdated
+func (c *Cache) Coverage(targets []filesystem.CoverageTarget, mirrorsIDs []int, staleAfter time.Duration, now time.Time) (*CoverageMatrix, error) {
+	// Mirrors carrying each file and size of the files in the repository
+	carriers := make(map[string]map[int]bool)
+	sizes := make(map[string]int64)
+	matrix := &CoverageMatrix{
+		Columns: make([]CoverageColumn, 0, len(targets)),
+		Rows:    []CoverageRow{},
+	}
+	for _, t := range targets {
+		matrix.Columns = append(matrix.Columns, CoverageColumn{t.Version, t.Scenario, t.Arch})
+		for _, f := range t.Files {
+			ids, err := c.getFileMirrors(f)
+			if err != nil {
+				return nil, err
+			}
+			carriers[f] = make(map[int]bool, len(ids))
+			for _, id := range ids {
+				carriers[f][id] = true
+			}
+			if fi, err := c.GetFileInfo(f); err == nil {
+				sizes[f] = fi.Size
+			}
+		}
+	}
+
+	for _, id := range mirrorsIDs {
+		m, err := c.GetMirror(id)
+		if err != nil {
+			return nil, err
+		}
+		if m.Private {
+			continue
+		}
+
+		row := CoverageRow{
+			Name:         m.Name,
+			HttpURL:      m.HttpURL,
+			Country:      m.Country,
+			CountryCodes: strings.Fields(m.CountryCodes),
+			Enabled:      m.Enabled,
+			Up:           m.Up,
+			Cells:        make([]CoverageCell, 0, len(targets)),
+		}
+		for _, t := range targets {
+			carried := 0
+			mismatch := false
+			for _, f := range t.Files {
+				if !carriers[f][m.ID] {
+					continue
+				}
+				carried++
+				if sizes[f] > 0 {
+					if fi, err := c.GetFileInfoMirror(m.ID, f); err == nil && fi.Size > 0 && fi.Size != sizes[f] {
+						mismatch = true
+					}
+				}
+			}
+			cell := CoverageCell{
+				State: coverageState(carried, len(t.Files), mismatch, m.LastSuccessfulSync.Time, staleAfter, now),
+			}
+			if carried > 0 && !m.LastSuccessfulSync.IsZero() {
+				lastSeen := m.LastSuccessfulSync.Time
+				cell.LastSeen = &lastSeen
+			}
+			row.Cells = append(row.Cells, cell)
+		}
+		matrix.Rows = append(matrix.Rows, row)
+	}
+	return matrix, nil
+}
+
+// coverageState returns the state of a column on a mirror carrying the given
+// number of its files: stale if some are missing or differ from the
+// repository, or if the last successful scan of the mirror is outdated
+func coverageState(carried, total int, mismatch bool, lastSync time.Time, staleAfter time.Duration, now time.Time) string {
+	if carried == 0 {
+		return CoverageMissing
+	}
+	if carried < total || mismatch || lastSync.IsZero() || (staleAfter > 0 && now.Sub(lastSync) > staleAfter) {
+		return CoverageStale
+	}
+	return CoveragePresent
+}
@@ -0,0 +1,39 @@
+// Copyright (c) 2014-2019 Ludovic Fauvet
+// Licensed under the MIT license
+
+package mirrors
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCoverageState(t *testing.T) {
+	now := time.Now()
+	recent := now.Add(-time.Hour)
+	old := now.Add(-24 * time.Hour)
+
+	tests := []struct {
+		carried, total int
+		mismatch       bool
+		lastSync       time.Time
+		expected       string
+	}{
+		{0, 2, false, recent, CoverageMissing},
+		{2, 2, false, recent, CoveragePresent},
+		{1, 2, false, recent, CoverageStale},
+		{2, 2, true, recent, CoverageStale},
+		{2, 2, false, old, CoverageStale},
+		{2, 2, false, time.Time{}, CoverageStale},
+	}
+	for i, test := range tests {
+		state := coverageState(test.carried, test.total, test.mismatch, test.lastSync, 3*time.Hour, now)
+		if state != test.expected {
+			t.Fatalf("Test %d: expected %q, got %q", i, test.expected, state)
+		}
+	}
+
+	if coverageState(2, 2, false, old, 0, now) != CoveragePresent {
+		t.Fatalf("The age of the last scan must be ignored without an interval")
+	}
+}
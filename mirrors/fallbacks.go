@@ -0,0 +1,92 @@
+// Copyright (c) 2014-2019 Ludovic Fauvet
+// Licensed under the MIT license
+
+package mirrors
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	. "github.com/opensourceways/mirrorbits/config"
+	"github.com/opensourceways/mirrorbits/network"
+)
+
+// The state of the fallbacks is kept in memory since they are
+// precisely used when the database might not be reachable
+var fallbackStates = struct {
+	sync.RWMutex
+	down map[string]string
+}{
+	down: make(map[string]string),
+}
+
+// SetFallbackState records the result of the health check of the fallback
+// with the given URL, reason being the cause of the failure
+func SetFallbackState(url string, up bool, reason string) {
+	fallbackStates.Lock()
+	defer fallbackStates.Unlock()
+
+	if up {
+		delete(fallbackStates.down, url)
+	} else {
+		fallbackStates.down[url] = reason
+	}
+}
+
+// GetFallbacks returns the configured fallbacks as mirrors, the fallbacks
+// found down by the last health check being excluded. Fallbacks are given
+// negative IDs in the order of the configuration.
+func GetFallbacks(clientInfo network.GeoIPRecord) (mlist Mirrors, excluded Mirrors) {
+	fallbackStates.RLock()
+	defer fallbackStates.RUnlock()
+
+	for i, f := range GetConfig().Fallbacks {
+		code := strings.ToUpper(f.CountryCode)
+		code, country := network.ApplyRegionAlias(code, f.Country)
+		m := Mirror{
+			ID:               -(i + 1),
+			Name:             f.Name,
+			HttpURL:          f.URL,
+			CountryCodes:     code,
+			Country:          country,
+			CountryFields:    []string{code},
+			ContinentCode:    strings.ToUpper(f.ContinentCode),
+			Latitude:         f.Latitude,
+			Longitude:        f.Longitude,
+			Asnum:            f.ASNum,
+			ASNGroup:         network.ASNGroupOf(f.ASNum),
+			NetworkBandwidth: f.NetworkBandwidth,
+			Enabled:          true,
+			Up:               true,
+		}
+		if f.Latitude != 0 || f.Longitude != 0 {
+			m.setDistance(clientInfo)
+		}
+		if reason, ok := fallbackStates.down[f.URL]; ok {
+			m.Up = false
+			m.ExcludeReason = reason
+			excluded = append(excluded, m)
+			continue
+		}
+		mlist = append(mlist, m)
+	}
+	return
+}
+
+// SelectFallbacks returns the fallbacks ranked for the client. The fallbacks
+// found down are used as a last resort when all of them are, their last
+// health check possibly being outdated.
+func SelectFallbacks(clientInfo network.GeoIPRecord) Mirrors {
+	mlist, excluded := GetFallbacks(clientInfo)
+	if len(mlist) == 0 {
+		mlist = excluded
+	}
+	sort.Sort(ByRank{Mirrors: mlist, ClientInfo: clientInfo})
+	return mlist
+}
+
+// IsFallback returns true if the mirror is one of the configured fallbacks
+func (m *Mirror) IsFallback() bool {
+	return m.ID < 0
+}
@@ -0,0 +1,91 @@
+// Copyright (c) 2014-2019 Ludovic Fauvet
+// Licensed under the MIT license
+
+package mirrors
+
+import (
+	"testing"
+
+	. "github.com/opensourceways/mirrorbits/config"
+	"github.com/opensourceways/mirrorbits/network"
+)
+
+func TestGetFallbacks(t *testing.T) {
+	SetConfiguration(&Configuration{
+		Fallbacks: []Fallback{
+			{URL: "http://fallback1.mirrorbits/", Name: "fallback1", CountryCode: "fr", ContinentCode: "eu", Latitude: 48.85, Longitude: 2.35, ASNum: 1234},
+			{URL: "http://fallback2.mirrorbits/", Name: "fallback2", CountryCode: "cn", ContinentCode: "as"},
+		},
+	})
+	defer SetFallbackState("http://fallback2.mirrorbits/", true, "")
+
+	clientInfo := network.GeoIPRecord{
+		CountryCode:   "FR",
+		ContinentCode: "EU",
+		Latitude:      45.76,
+		Longitude:     4.83,
+	}
+
+	mlist, excluded := GetFallbacks(clientInfo)
+	if len(mlist) != 2 || len(excluded) != 0 {
+		t.Fatalf("Expected 2 fallbacks, got %d (%d excluded)", len(mlist), len(excluded))
+	}
+	if mlist[0].ID != -1 || !mlist[0].IsFallback() || mlist[0].CountryCodes != "FR" || mlist[0].Asnum != 1234 {
+		t.Fatalf("Invalid fallback %+v", mlist[0])
+	}
+	if mlist[0].Distance < 350 || mlist[0].Distance > 450 {
+		t.Fatalf("Expected a distance of about 400km, got %f", mlist[0].Distance)
+	}
+	if mlist[1].Distance != 0 {
+		t.Fatalf("Fallbacks without coordinates must have no distance, got %f", mlist[1].Distance)
+	}
+
+	SetFallbackState("http://fallback2.mirrorbits/", false, "Unreachable")
+	mlist, excluded = GetFallbacks(clientInfo)
+	if len(mlist) != 1 || mlist[0].Name != "fallback1" {
+		t.Fatalf("Expected fallback1 only, got %d fallbacks", len(mlist))
+	}
+	if len(excluded) != 1 || excluded[0].Up || excluded[0].ExcludeReason != "Unreachable" {
+		t.Fatalf("Expected fallback2 to be excluded as unreachable")
+	}
+
+	SetFallbackState("http://fallback2.mirrorbits/", true, "")
+	if mlist, _ = GetFallbacks(clientInfo); len(mlist) != 2 {
+		t.Fatalf("Expected fallback2 to be back, got %d fallbacks", len(mlist))
+	}
+}
+
+func TestSelectFallbacks(t *testing.T) {
+	SetConfiguration(&Configuration{
+		Fallbacks: []Fallback{
+			{URL: "http://fallback1.mirrorbits/", Name: "fallback1", CountryCode: "cn", ContinentCode: "as", Latitude: 39.90, Longitude: 116.40},
+			{URL: "http://fallback2.mirrorbits/", Name: "fallback2", CountryCode: "fr", ContinentCode: "eu", Latitude: 48.85, Longitude: 2.35},
+		},
+	})
+	defer SetFallbackState("http://fallback1.mirrorbits/", true, "")
+	defer SetFallbackState("http://fallback2.mirrorbits/", true, "")
+
+	clientInfo := network.GeoIPRecord{
+		CountryCode:   "FR",
+		ContinentCode: "EU",
+		Latitude:      45.76,
+		Longitude:     4.83,
+	}
+
+	mlist := SelectFallbacks(clientInfo)
+	if len(mlist) != 2 || mlist[0].Name != "fallback2" {
+		t.Fatalf("Expected fallback2 to be ranked first, got %d fallbacks", len(mlist))
+	}
+
+	SetFallbackState("http://fallback2.mirrorbits/", false, "Unreachable")
+	if mlist = SelectFallbacks(clientInfo); len(mlist) != 1 || mlist[0].Name != "fallback1" {
+		t.Fatalf("Expected fallback1 only, got %d fallbacks", len(mlist))
+	}
+
+	// The fallbacks down are used when there is nothing else
+	SetFallbackState("http://fallback1.mirrorbits/", false, "Unreachable")
+	mlist = SelectFallbacks(clientInfo)
+	if len(mlist) != 2 || mlist[0].Name != "fallback2" || mlist[0].Up {
+		t.Fatalf("Expected the fallbacks down to be ranked, got %d fallbacks", len(mlist))
+	}
+}
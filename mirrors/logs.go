@@ -6,6 +6,8 @@ package mirrors
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/opensourceways/mirrorbits/core"
@@ -32,6 +34,60 @@ const (
 	LOGTYPE_SCANCOMPLETED
 )
 
+var logTypeNames = map[LogType]string{
+	LOGTYPE_ERROR:         "error",
+	LOGTYPE_ADDED:         "added",
+	LOGTYPE_EDITED:        "edited",
+	LOGTYPE_ENABLED:       "enabled",
+	LOGTYPE_DISABLED:      "disabled",
+	LOGTYPE_STATECHANGED:  "statechanged",
+	LOGTYPE_SCANSTARTED:   "scanstarted",
+	LOGTYPE_SCANCOMPLETED: "scancompleted",
+}
+
+// String returns the short name of the log type
+func (t LogType) String() string {
+	if name, ok := logTypeNames[t]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+// ParseLogType returns the LogType matching the given short name
+func ParseLogType(name string) (LogType, bool) {
+	name = strings.ToLower(name)
+	for t, n := range logTypeNames {
+		if n == name {
+			return t, true
+		}
+	}
+	return 0, false
+}
+
+// LogHook is a function called each time an action is pushed into the logs
+type LogHook func(logAction LogAction)
+
+var (
+	logHooks     []LogHook
+	logHooksLock sync.RWMutex
+)
+
+// AddLogHook registers a hook called after each successful PushLog.
+// Hooks are called synchronously and must not block.
+func AddLogHook(hook LogHook) {
+	logHooksLock.Lock()
+	defer logHooksLock.Unlock()
+	logHooks = append(logHooks, hook)
+}
+
+func runLogHooks(logAction LogAction) {
+	logHooksLock.RLock()
+	defer logHooksLock.RUnlock()
+	for _, hook := range logHooks {
+		hook(logAction)
+	}
+}
+
 func typeToInstance(typ LogType) LogAction {
 	switch LogType(typ) {
 	case LOGTYPE_ERROR:
@@ -270,7 +326,12 @@ func PushLog(r *database.Redis, logAction LogAction) error {
 	}
 
 	_, err = conn.Do("RPUSH", key, value)
-	return err
+	if err != nil {
+		return err
+	}
+
+	runLogHooks(logAction)
+	return nil
 }
 
 func ReadLogs(r *database.Redis, mirrorid, max int) ([]string, error) {
@@ -54,6 +54,10 @@ type LRUCache struct {
 
 	// How many bytes we are limiting the cache to.
 	capacity uint64
+
+	// Number of lookups that found or missed the key
+	hits   uint64
+	misses uint64
 }
 
 // Value that go into LRUCache need to satisfy this interface.
@@ -90,8 +94,10 @@ func (lru *LRUCache) Get(key string) (v Value, ok bool) {
 
 	element := lru.table[key]
 	if element == nil {
+		lru.misses++
 		return nil, false
 	}
+	lru.hits++
 	lru.moveToFront(element)
 	return element.Value.(*entry).value, true
 }
@@ -165,6 +171,13 @@ func (lru *LRUCache) Stats() (length, size, capacity uint64, oldest time.Time) {
 	return uint64(lru.list.Len()), lru.size, lru.capacity, oldest
 }
 
+// Lookups returns the number of lookups that found or missed the key
+func (lru *LRUCache) Lookups() (hits, misses uint64) {
+	lru.mu.Lock()
+	defer lru.mu.Unlock()
+	return lru.hits, lru.misses
+}
+
 // StatsJSON returns the stats as JSON
 func (lru *LRUCache) StatsJSON() string {
 	if lru == nil {
@@ -194,3 +194,16 @@ func TestLRUIsEvicted(t *testing.T) {
 		t.Error("Least recently used element was not evicted.")
 	}
 }
+
+func TestLRULookups(t *testing.T) {
+	cache := NewLRUCache(100)
+	cache.Set("key", &CacheValue{1})
+
+	cache.Get("key")
+	cache.Get("key")
+	cache.Get("missing")
+
+	if hits, misses := cache.Lookups(); hits != 2 || misses != 1 {
+		t.Errorf("cache.Lookups() = %v, %v, expected 2, 1", hits, misses)
+	}
+}
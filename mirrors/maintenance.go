@@ -0,0 +1,170 @@
+// Copyright (c) 2014-2019 Ludovic Fauvet
+// Licensed under the MIT license
+
+package mirrors
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/opensourceways/mirrorbits/database"
+)
+
+var (
+	// ErrInvalidMaintenanceWindow is returned when the end of a window is not after its start
+	ErrInvalidMaintenanceWindow = errors.New("The end of the maintenance window must be after its start")
+	// ErrMaintenanceWindowNotFound is returned when removing an unknown window
+	ErrMaintenanceWindowNotFound = errors.New("Maintenance window not found")
+)
+
+// MaintenanceWindow is a period during which a mirror is expected to be unavailable
+type MaintenanceWindow struct {
+	ID     int
+	Start  time.Time
+	End    time.Time
+	Reason string `json:",omitempty"`
+}
+
+// IsActive returns true if t is within the window
+func (w MaintenanceWindow) IsActive(t time.Time) bool {
+	return !t.Before(w.Start) && t.Before(w.End)
+}
+
+// MaintenanceWindows is a list of maintenance windows stored
+// as a JSON document in the mirror hash
+type MaintenanceWindows []MaintenanceWindow
+
+// RedisArg serializes the maintenance windows
+func (w MaintenanceWindows) RedisArg() interface{} {
+	if len(w) == 0 {
+		return ""
+	}
+	b, _ := json.Marshal([]MaintenanceWindow(w))
+	return b
+}
+
+// RedisScan deserializes the maintenance windows
+func (w *MaintenanceWindows) RedisScan(src interface{}) error {
+	var b []byte
+	switch src := src.(type) {
+	case []byte:
+		b = src
+	case string:
+		b = []byte(src)
+	default:
+		return fmt.Errorf("cannot convert from %T to %T", src, w)
+	}
+	*w = nil
+	if len(b) == 0 {
+		return nil
+	}
+	return json.Unmarshal(b, (*[]MaintenanceWindow)(w))
+}
+
+// Active returns the window active at the given time, if any
+func (w MaintenanceWindows) Active(t time.Time) *MaintenanceWindow {
+	for i := range w {
+		if w[i].IsActive(t) {
+			return &w[i]
+		}
+	}
+	return nil
+}
+
+// InMaintenance returns true if the mirror is within a maintenance window
+func (m *Mirror) InMaintenance() bool {
+	return m.Maintenance.Active(time.Now()) != nil
+}
+
+// GetMaintenanceWindows returns the maintenance windows of a mirror
+func GetMaintenanceWindows(r *database.Redis, id int) (MaintenanceWindows, error) {
+	conn := r.Get()
+	defer conn.Close()
+
+	return getMaintenanceWindows(conn, id)
+}
+
+func getMaintenanceWindows(conn redis.Conn, id int) (MaintenanceWindows, error) {
+	var windows MaintenanceWindows
+
+	reply, err := conn.Do("HGET", fmt.Sprintf("MIRROR_%d", id), "maintenance")
+	if err != nil || reply == nil {
+		return nil, err
+	}
+	err = windows.RedisScan(reply)
+	return windows, err
+}
+
+// updateMaintenanceWindows replaces the maintenance windows of an existing
+// mirror by the ones returned by update given the current ones
+func updateMaintenanceWindows(conn redis.Conn, id int, update func(MaintenanceWindows) (MaintenanceWindows, error)) error {
+	return updateMirrorField(conn, id, "maintenance", func(reply interface{}) (interface{}, error) {
+		var windows MaintenanceWindows
+		if reply != nil {
+			if err := windows.RedisScan(reply); err != nil {
+				return nil, err
+			}
+		}
+		windows, err := update(windows)
+		if err != nil {
+			return nil, err
+		}
+		return windows.RedisArg(), nil
+	})
+}
+
+// AddMaintenanceWindow schedules a new maintenance window for the given mirror.
+// Expired windows are removed at the same time.
+func AddMaintenanceWindow(r *database.Redis, id int, start, end time.Time, reason string) (MaintenanceWindow, error) {
+	if !end.After(start) {
+		return MaintenanceWindow{}, ErrInvalidMaintenanceWindow
+	}
+
+	conn := r.Get()
+	defer conn.Close()
+
+	var window MaintenanceWindow
+	err := updateMaintenanceWindows(conn, id, func(windows MaintenanceWindows) (MaintenanceWindows, error) {
+		now := time.Now()
+		lastID := 0
+		kept := make(MaintenanceWindows, 0, len(windows)+1)
+		for _, w := range windows {
+			if w.ID > lastID {
+				lastID = w.ID
+			}
+			if w.End.After(now) {
+				kept = append(kept, w)
+			}
+		}
+
+		window = MaintenanceWindow{
+			ID:     lastID + 1,
+			Start:  start.UTC(),
+			End:    end.UTC(),
+			Reason: reason,
+		}
+		return append(kept, window), nil
+	})
+	if err != nil {
+		return MaintenanceWindow{}, err
+	}
+	return window, nil
+}
+
+// RemoveMaintenanceWindow removes a maintenance window from the given mirror
+func RemoveMaintenanceWindow(r *database.Redis, id, windowID int) error {
+	conn := r.Get()
+	defer conn.Close()
+
+	return updateMaintenanceWindows(conn, id, func(windows MaintenanceWindows) (MaintenanceWindows, error) {
+		for i, w := range windows {
+			if w.ID == windowID {
+				return append(windows[:i], windows[i+1:]...), nil
+			}
+		}
+		return nil, ErrMaintenanceWindowNotFound
+	})
+}
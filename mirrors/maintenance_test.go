@@ -0,0 +1,178 @@
+// Copyright (c) 2014-2019 Ludovic Fauvet
+// Licensed under the MIT license
+
+package mirrors
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/opensourceways/mirrorbits/database"
+	. "github.com/opensourceways/mirrorbits/testing"
+	"github.com/rafaeljusto/redigomock"
+)
+
+func TestMaintenanceWindows_Active(t *testing.T) {
+	now := time.Now()
+	windows := MaintenanceWindows{
+		{ID: 1, Start: now.Add(-2 * time.Hour), End: now.Add(-time.Hour)},
+		{ID: 2, Start: now.Add(-time.Minute), End: now.Add(time.Hour)},
+		{ID: 3, Start: now.Add(2 * time.Hour), End: now.Add(3 * time.Hour)},
+	}
+
+	if w := windows.Active(now); w == nil || w.ID != 2 {
+		t.Fatalf("Expected window 2 to be active, got %v", w)
+	}
+	if w := windows.Active(now.Add(90 * time.Minute)); w != nil {
+		t.Fatalf("Expected no active window, got %v", w)
+	}
+	if w := windows.Active(now.Add(3 * time.Hour)); w != nil {
+		t.Fatalf("The end of a window must be exclusive")
+	}
+
+	m := Mirror{Maintenance: windows}
+	if !m.InMaintenance() {
+		t.Fatalf("Mirror should be in maintenance")
+	}
+}
+
+func TestMaintenanceWindows_Redis(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	windows := MaintenanceWindows{
+		{ID: 1, Start: now, End: now.Add(time.Hour), Reason: "Disk replacement"},
+	}
+
+	values := []interface{}{[]byte("ID"), []byte("1"), []byte("maintenance"), windows.RedisArg()}
+
+	var m Mirror
+	if err := redis.ScanStruct(values, &m); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if len(m.Maintenance) != 1 || !m.Maintenance[0].Start.Equal(now) || m.Maintenance[0].Reason != "Disk replacement" {
+		t.Fatalf("Unexpected windows: %v", m.Maintenance)
+	}
+
+	values = []interface{}{[]byte("maintenance"), []byte("")}
+	if err := redis.ScanStruct(values, &m); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if len(m.Maintenance) != 0 {
+		t.Fatalf("Expected no window, got %v", m.Maintenance)
+	}
+}
+
+// mockMirrorUpdate registers the commands watching an existing mirror
+// during the update of one of its fields
+func mockMirrorUpdate(mock *redigomock.Conn, key string) {
+	mock.Command("WATCH", key).Expect("OK")
+	mock.Command("UNWATCH").Expect("OK")
+	mock.Command("EXISTS", key).Expect(int64(1))
+	mock.Command("MULTI").Expect("OK")
+	mock.Command("EXEC").Expect([]interface{}{int64(0)})
+}
+
+func TestAddMaintenanceWindow(t *testing.T) {
+	mock, conn := PrepareRedisTest()
+
+	now := time.Now()
+
+	if _, err := AddMaintenanceWindow(conn, 1, now, now, ""); err != ErrInvalidMaintenanceWindow {
+		t.Fatalf("Expected ErrInvalidMaintenanceWindow, got %v", err)
+	}
+
+	existing := MaintenanceWindows{
+		{ID: 4, Start: now.Add(-2 * time.Hour), End: now.Add(-time.Hour)},
+		{ID: 2, Start: now.Add(time.Hour), End: now.Add(2 * time.Hour)},
+	}
+
+	mockMirrorUpdate(mock, "MIRROR_1")
+	mock.Command("HGET", "MIRROR_1", "maintenance").Expect(existing.RedisArg())
+	cmdPublish := mock.Command("PUBLISH", string(database.MIRROR_UPDATE), redigomock.NewAnyData()).Expect("ok")
+	cmdSet := mock.Command("HSET", "MIRROR_1", "maintenance", redigomock.NewAnyData()).Expect(int64(0))
+
+	w, err := AddMaintenanceWindow(conn, 1, now, now.Add(time.Hour), "Upgrade")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if w.ID != 5 {
+		t.Fatalf("Expected window ID 5, got %d", w.ID)
+	}
+	if mock.Stats(cmdSet) != 1 {
+		t.Fatalf("Windows not saved")
+	}
+	if mock.Stats(cmdPublish) != 1 {
+		t.Fatalf("Event MIRROR_UPDATE not published")
+	}
+}
+
+func TestRemoveMaintenanceWindow(t *testing.T) {
+	mock, conn := PrepareRedisTest()
+
+	now := time.Now()
+	existing := MaintenanceWindows{
+		{ID: 1, Start: now, End: now.Add(time.Hour)},
+	}
+
+	mockMirrorUpdate(mock, "MIRROR_1")
+	mock.Command("HGET", "MIRROR_1", "maintenance").Expect(existing.RedisArg())
+	mock.Command("PUBLISH", string(database.MIRROR_UPDATE), redigomock.NewAnyData()).Expect("ok")
+	cmdSet := mock.Command("HSET", "MIRROR_1", "maintenance", "").Expect(int64(0))
+
+	if err := RemoveMaintenanceWindow(conn, 1, 2); err != ErrMaintenanceWindowNotFound {
+		t.Fatalf("Expected ErrMaintenanceWindowNotFound, got %v", err)
+	}
+
+	if err := RemoveMaintenanceWindow(conn, 1, 1); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if mock.Stats(cmdSet) != 1 {
+		t.Fatalf("Windows not saved")
+	}
+}
+
+func TestAddMaintenanceWindow_UnknownMirror(t *testing.T) {
+	m, conn := PrepareMiniredisTest(t)
+
+	now := time.Now()
+	if _, err := AddMaintenanceWindow(conn, 2, now, now.Add(time.Hour), ""); err != ErrMirrorNotFound {
+		t.Fatalf("Expected ErrMirrorNotFound, got %v", err)
+	}
+	if err := RemoveMaintenanceWindow(conn, 2, 1); err != ErrMirrorNotFound {
+		t.Fatalf("Expected ErrMirrorNotFound, got %v", err)
+	}
+	if m.Exists("MIRROR_2") {
+		t.Fatalf("The unknown mirror must not be created")
+	}
+
+	m.HSet("MIRROR_1", "ID", "1")
+	w, err := AddMaintenanceWindow(conn, 1, now, now.Add(time.Hour), "Upgrade")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	windows, err := GetMaintenanceWindows(conn, 1)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if len(windows) != 1 || windows[0].ID != w.ID || windows[0].Reason != "Upgrade" {
+		t.Fatalf("Unexpected windows: %v", windows)
+	}
+}
+
+func TestAddMaintenanceWindow_Conflict(t *testing.T) {
+	mock, conn := PrepareRedisTest()
+
+	mockMirrorUpdate(mock, "MIRROR_1")
+	mock.Command("HGET", "MIRROR_1", "maintenance").Expect(nil)
+	// The mirror is modified between each read and write
+	cmdExec := mock.Command("EXEC").Expect(nil)
+	cmdSet := mock.Command("HSET", "MIRROR_1", "maintenance", redigomock.NewAnyData()).Expect(int64(0))
+
+	now := time.Now()
+	if _, err := AddMaintenanceWindow(conn, 1, now, now.Add(time.Hour), ""); err != ErrConcurrentUpdate {
+		t.Fatalf("Expected ErrConcurrentUpdate, got %v", err)
+	}
+	if mock.Stats(cmdExec) != maxUpdateAttempts || mock.Stats(cmdSet) != maxUpdateAttempts {
+		t.Fatalf("Expected %d attempts, got %d", maxUpdateAttempts, mock.Stats(cmdExec))
+	}
+}
@@ -4,8 +4,12 @@
 package mirrors
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"math/rand"
+	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
 	"time"
@@ -21,47 +25,60 @@ import (
 
 // Mirror is the structure representing all the information about a mirror
 type Mirror struct {
-	ID                          int              `redis:"ID" yaml:"-"`
-	Name                        string           `redis:"name" yaml:"Name"`
-	HttpURL                     string           `redis:"http" yaml:"HttpURL"`
-	RsyncURL                    string           `redis:"rsync" yaml:"RsyncURL"`
-	FtpURL                      string           `redis:"ftp" yaml:"FtpURL"`
-	SponsorName                 string           `redis:"sponsorName" yaml:"SponsorName"`
-	SponsorURL                  string           `redis:"sponsorURL" yaml:"SponsorURL"`
-	SponsorLogoURL              string           `redis:"sponsorLogo" yaml:"SponsorLogoURL"`
-	AdminName                   string           `redis:"adminName" yaml:"AdminName"`
-	AdminEmail                  string           `redis:"adminEmail" yaml:"AdminEmail"`
-	CustomData                  string           `redis:"customData" yaml:"CustomData"`
-	ContinentOnly               bool             `redis:"continentOnly" yaml:"ContinentOnly"`
-	CountryOnly                 bool             `redis:"countryOnly" yaml:"CountryOnly"`
-	ASOnly                      bool             `redis:"asOnly" yaml:"ASOnly"`
-	Score                       int              `redis:"score" yaml:"Score"`
-	Latitude                    float32          `redis:"latitude" yaml:"Latitude"`
-	Longitude                   float32          `redis:"longitude" yaml:"Longitude"`
-	NetworkBandwidth            int32            `redis:"networkBandwidth" yaml:"NetworkBandwidth"`
-	ContinentCode               string           `redis:"continentCode" yaml:"ContinentCode"`
-	CountryCodes                string           `redis:"countryCodes" yaml:"CountryCodes"`
-	Country                     string           `redis:"country" yaml:"Country"`
-	ExcludedCountryCodes        string           `redis:"excludedCountryCodes" yaml:"ExcludedCountryCodes"`
-	Asnum                       uint             `redis:"asnum" yaml:"ASNum"`
-	Comment                     string           `redis:"comment" yaml:"-"`
-	Enabled                     bool             `redis:"enabled" yaml:"Enabled"`
-	Up                          bool             `redis:"up" json:"-" yaml:"-"`
-	ExcludeReason               string           `redis:"excludeReason" json:",omitempty" yaml:"-"`
-	StateSince                  Time             `redis:"stateSince" json:",omitempty" yaml:"-"`
-	AllowRedirects              Redirects        `redis:"allowredirects" json:",omitempty" yaml:"AllowRedirects"`
-	TZOffset                    int64            `redis:"tzoffset" json:"-" yaml:"-"` // timezone offset in ms
-	Distance                    float32          `redis:"-" yaml:"-"`
-	CountryFields               []string         `redis:"-" json:"-" yaml:"-"`
-	ExcludedCountryFields       []string         `redis:"-" json:"-" yaml:"-"`
-	Filepath                    string           `redis:"-" json:"-" yaml:"-"`
-	Weight                      float32          `redis:"-" json:"-" yaml:"-"`
-	ComputedScore               [3]int           `redis:"-" yaml:"-" json:",omitempty" `
-	LastSync                    Time             `redis:"lastSync" yaml:"-"`
-	LastSuccessfulSync          Time             `redis:"lastSuccessfulSync" yaml:"-"`
-	LastSuccessfulSyncProtocol  core.ScannerType `redis:"lastSuccessfulSyncProtocol" yaml:"-"`
-	LastSuccessfulSyncPrecision core.Precision   `redis:"lastSuccessfulSyncPrecision" yaml:"-"`
-	LastModTime                 Time             `redis:"lastModTime" yaml:"-"`
+	ID                          int                 `redis:"ID" yaml:"-"`
+	Name                        string              `redis:"name" yaml:"Name"`
+	HttpURL                     string              `redis:"http" yaml:"HttpURL"`
+	RsyncURL                    string              `redis:"rsync" yaml:"RsyncURL"`
+	FtpURL                      string              `redis:"ftp" yaml:"FtpURL"`
+	SponsorName                 string              `redis:"sponsorName" yaml:"SponsorName"`
+	SponsorURL                  string              `redis:"sponsorURL" yaml:"SponsorURL"`
+	SponsorLogoURL              string              `redis:"sponsorLogo" yaml:"SponsorLogoURL"`
+	AdminName                   string              `redis:"adminName" yaml:"AdminName"`
+	AdminEmail                  string              `redis:"adminEmail" yaml:"AdminEmail"`
+	CustomData                  string              `redis:"customData" yaml:"CustomData"`
+	ContinentOnly               bool                `redis:"continentOnly" yaml:"ContinentOnly"`
+	CountryOnly                 bool                `redis:"countryOnly" yaml:"CountryOnly"`
+	ASOnly                      bool                `redis:"asOnly" yaml:"ASOnly"`
+	Private                     bool                `redis:"private" json:",omitempty" yaml:"Private"`
+	Score                       int                 `redis:"score" yaml:"Score"`
+	Latitude                    float32             `redis:"latitude" yaml:"Latitude"`
+	Longitude                   float32             `redis:"longitude" yaml:"Longitude"`
+	NetworkBandwidth            int32               `redis:"networkBandwidth" yaml:"NetworkBandwidth"`
+	ContinentCode               string              `redis:"continentCode" yaml:"ContinentCode"`
+	CountryCodes                string              `redis:"countryCodes" yaml:"CountryCodes"`
+	Country                     string              `redis:"country" yaml:"Country"`
+	SubdivisionCode             string              `redis:"subdivisionCode" json:",omitempty" yaml:"SubdivisionCode"`
+	ExcludedCountryCodes        string              `redis:"excludedCountryCodes" yaml:"ExcludedCountryCodes"`
+	Asnum                       uint                `redis:"asnum" yaml:"ASNum"`
+	ASNGroup                    string              `redis:"asnGroup" json:",omitempty" yaml:"-"` // derived from Asnum and the ASNGroups
+	Comment                     string              `redis:"comment" yaml:"-"`
+	Enabled                     bool                `redis:"enabled" yaml:"Enabled"`
+	Up                          bool                `redis:"up" json:"-" yaml:"-"`
+	ExcludeReason               string              `redis:"excludeReason" json:",omitempty" yaml:"-"`
+	StateSince                  Time                `redis:"stateSince" json:",omitempty" yaml:"-"`
+	AllowRedirects              Redirects           `redis:"allowredirects" json:",omitempty" yaml:"AllowRedirects"`
+	AllowedRedirectDomains      string              `redis:"allowedRedirectDomains" json:",omitempty" yaml:"AllowedRedirectDomains"`
+	ResolvedURL                 string              `redis:"resolvedURL" json:",omitempty" yaml:"-"`
+	Maintenance                 MaintenanceWindows  `redis:"maintenance" json:",omitempty" yaml:"-"`
+	Versions                    VersionAvailability `redis:"versions" json:",omitempty" yaml:"-"`
+	Networks                    NetworkRules        `redis:"networks" json:",omitempty" yaml:"-"`
+	ShareCaps                   ShareCaps           `redis:"shareCaps" json:",omitempty" yaml:"ShareCaps"`
+	TZOffset                    int64               `redis:"tzoffset" json:"-" yaml:"-"` // timezone offset in ms
+	Distance                    float32             `redis:"-" yaml:"-"`
+	CountryFields               []string            `redis:"-" json:"-" yaml:"-"`
+	ExcludedCountryFields       []string            `redis:"-" json:"-" yaml:"-"`
+	Filepath                    string              `redis:"-" json:"-" yaml:"-"`
+	Weight                      float32             `redis:"-" json:"-" yaml:"-"`
+	Utilization                 float32             `redis:"-" json:",omitempty" yaml:"-"` // estimated fraction of the NetworkBandwidth in use
+	NetworkMatch                string              `redis:"-" json:",omitempty" yaml:"-"` // network rule matching the client
+	ShareCapped                 string              `redis:"-" json:",omitempty" yaml:"-"` // share cap reached for the client
+	ASNGroupMatch               string              `redis:"-" json:",omitempty" yaml:"-"` // ASN group shared with the client
+	ComputedScore               [3]int              `redis:"-" yaml:"-" json:",omitempty" `
+	LastSync                    Time                `redis:"lastSync" yaml:"-"`
+	LastSuccessfulSync          Time                `redis:"lastSuccessfulSync" yaml:"-"`
+	LastSuccessfulSyncProtocol  core.ScannerType    `redis:"lastSuccessfulSyncProtocol" yaml:"-"`
+	LastSuccessfulSyncPrecision core.Precision      `redis:"lastSuccessfulSyncPrecision" yaml:"-"`
+	LastModTime                 Time                `redis:"lastModTime" yaml:"-"`
 
 	FileInfo *filesystem.FileInfo `redis:"-" json:"-" yaml:"-"` // Details of the requested file on this specific mirror
 }
@@ -102,6 +119,15 @@ func (m ByRank) Less(i, j int) bool {
 		} else if m.ClientInfo.ASNum == m.Mirrors[j].Asnum {
 			return false
 		}
+		if m.ClientInfo.ASNGroup != "" {
+			if m.ClientInfo.ASNGroup == m.Mirrors[i].ASNGroup {
+				if m.Mirrors[i].ASNGroup != m.Mirrors[j].ASNGroup {
+					return true
+				}
+			} else if m.ClientInfo.ASNGroup == m.Mirrors[j].ASNGroup {
+				return false
+			}
+		}
 
 		//TODO Simplify me
 		if m.ClientInfo.CountryCode != "" {
@@ -239,6 +265,51 @@ func SetMirrorState(r *database.Redis, id int, state bool, reason string) error
 	return err
 }
 
+// maxUpdateAttempts is the number of attempts to update a field of a
+// mirror modified concurrently
+const maxUpdateAttempts = 10
+
+// updateMirrorField replaces a field of an existing mirror by the value
+// returned by update given the current one, nil if unset. The mirror is
+// watched during the update so a concurrent modification starts it over.
+func updateMirrorField(conn redis.Conn, id int, field string, update func(reply interface{}) (interface{}, error)) error {
+	key := fmt.Sprintf("MIRROR_%d", id)
+
+	for i := 0; i < maxUpdateAttempts; i++ {
+		if _, err := conn.Do("WATCH", key); err != nil {
+			return err
+		}
+		exists, err := redis.Bool(conn.Do("EXISTS", key))
+		if err == nil && !exists {
+			err = ErrMirrorNotFound
+		}
+		var value interface{}
+		if err == nil {
+			var reply interface{}
+			if reply, err = conn.Do("HGET", key, field); err == nil {
+				value, err = update(reply)
+			}
+		}
+		if err != nil {
+			conn.Do("UNWATCH")
+			return err
+		}
+
+		conn.Send("MULTI")
+		conn.Send("HSET", key, field, value)
+		reply, err := conn.Do("EXEC")
+		if err != nil {
+			return err
+		}
+		if reply != nil {
+			// Publish update
+			database.Publish(conn, database.MIRROR_UPDATE, strconv.Itoa(id))
+			return nil
+		}
+	}
+	return ErrConcurrentUpdate
+}
+
 // Results is the resulting struct of a request and is
 // used by the renderers to generate the final page.
 type Results struct {
@@ -253,6 +324,49 @@ type Results struct {
 	LocalJSPath  string
 }
 
+// Explanation details how the mirrors were selected for a client and a
+// file, it is returned by the explain mode of the HTTP server and the CLI.
+type Explanation struct {
+	IP         string
+	ClientInfo network.GeoIPRecord
+	FileInfo   filesystem.FileInfo
+	Fallback   bool `json:",omitempty"`
+	Mirrors    []ExplainedMirror
+}
+
+// ExplainedMirror details the evaluation of a candidate mirror
+type ExplainedMirror struct {
+	ID            int
+	Name          string
+	Rank          int // position in the final order, 0 if excluded
+	Distance      float32
+	CountryScore  int
+	Score         int
+	DistanceScore int
+	Weight        float32 `json:",omitempty"`
+	Utilization   float32 `json:",omitempty"`
+	NetworkMatch  string  `json:",omitempty"`
+	ShareCapped   string  `json:",omitempty"`
+	ASNGroupMatch string  `json:",omitempty"`
+	FileMatch     string
+	ExcludeReason string `json:",omitempty"`
+}
+
+var (
+	// ErrRedirect is returned when a mirror redirects while it is not allowed to
+	ErrRedirect = errors.New("Redirect not allowed")
+	// ErrRedirectDomain is returned when a mirror redirects to a domain outside its allowed list
+	ErrRedirectDomain = errors.New("Redirect to unauthorized domain")
+	// ErrTooManyRedirects is returned when a mirror exceeds the maximum number of redirects
+	ErrTooManyRedirects = errors.New("Too many redirects")
+	// ErrMirrorNotFound is returned when updating an unknown mirror
+	ErrMirrorNotFound = errors.New("Mirror not found")
+	// ErrConcurrentUpdate is returned when a mirror keeps being modified during an update
+	ErrConcurrentUpdate = errors.New("Mirror modified concurrently, please retry")
+)
+
+const maxRedirects = 10
+
 // Redirects is handling the per-mirror authorization of HTTP redirects
 type Redirects int
 
@@ -268,6 +382,124 @@ func (r *Redirects) Allowed() bool {
 	}
 }
 
+// RedirectContext returns a copy of ctx carrying the redirect policy of the mirror
+// so it can be enforced by CheckRedirect.
+func RedirectContext(ctx context.Context, m *Mirror) context.Context {
+	ctx = context.WithValue(ctx, core.ContextAllowRedirects, m.AllowRedirects)
+	ctx = context.WithValue(ctx, core.ContextAllowedRedirectDomains, strings.Fields(m.AllowedRedirectDomains))
+	ctx = context.WithValue(ctx, core.ContextMirrorID, m.ID)
+	ctx = context.WithValue(ctx, core.ContextMirrorName, m.Name)
+	return ctx
+}
+
+// CheckRedirect is meant to be used as the redirect policy of the HTTP clients
+// talking to the mirrors. It returns an error if the redirection is not
+// authorized by the policy found in the request context (see RedirectContext).
+func CheckRedirect(req *http.Request, via []*http.Request) error {
+	redirects, _ := req.Context().Value(core.ContextAllowRedirects).(Redirects)
+	domains, _ := req.Context().Value(core.ContextAllowedRedirectDomains).([]string)
+	name := req.Context().Value(core.ContextMirrorName)
+
+	var err error
+	if !redirects.Allowed() {
+		err = ErrRedirect
+	} else if len(via) >= maxRedirects {
+		err = ErrTooManyRedirects
+	} else if len(via) > 0 && req.URL.Hostname() != via[0].URL.Hostname() && !IsAllowedRedirectDomain(req.URL.Hostname(), domains) {
+		err = ErrRedirectDomain
+	}
+
+	if err != nil && len(via) > 0 {
+		log.Warningf("Unauthorized redirection for %v: %s => %s", name, via[len(via)-1].URL.String(), req.URL.String())
+	}
+	return err
+}
+
+// IsAllowedRedirectDomain returns true if host is one of the given domains or
+// one of their subdomains. An empty list allows all domains.
+func IsAllowedRedirectDomain(host string, domains []string) bool {
+	if len(domains) == 0 {
+		return true
+	}
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	for _, d := range domains {
+		d = strings.ToLower(strings.TrimPrefix(strings.TrimSuffix(d, "."), "."))
+		if host == d || strings.HasSuffix(host, "."+d) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsRedirectError returns true if err has been caused by the redirect policy
+func IsRedirectError(err error) bool {
+	if err == nil {
+		return false
+	}
+	for _, e := range []error{ErrRedirect, ErrRedirectDomain, ErrTooManyRedirects} {
+		if errors.Is(err, e) || strings.Contains(err.Error(), e.Error()) {
+			return true
+		}
+	}
+	return false
+}
+
+// ResolvedBaseURL returns the base URL of a mirror after following the redirects
+// of a request made to path. It returns an empty string if the final URL does not
+// end with path.
+func ResolvedBaseURL(final *url.URL, path string) string {
+	if final == nil {
+		return ""
+	}
+	p := strings.TrimPrefix(path, "/")
+	if !strings.HasSuffix(final.Path, p) {
+		return ""
+	}
+	u := *final
+	u.Path = strings.TrimSuffix(final.Path, p)
+	u.RawPath = ""
+	u.RawQuery = ""
+	u.Fragment = ""
+	return utils.NormalizeURL(u.String())
+}
+
+// SetMirrorResolvedURL records the URL a mirror effectively resolves to after redirects
+func SetMirrorResolvedURL(r *database.Redis, id int, resolved string) error {
+	conn := r.Get()
+	defer conn.Close()
+
+	key := fmt.Sprintf("MIRROR_%d", id)
+
+	previous, err := redis.String(conn.Do("HGET", key, "resolvedURL"))
+	if err != nil && err != redis.ErrNil {
+		return err
+	}
+	if previous == resolved {
+		return nil
+	}
+
+	_, err = conn.Do("HSET", key, "resolvedURL", resolved)
+	if err == nil {
+		// Publish update
+		database.Publish(conn, database.MIRROR_UPDATE, strconv.Itoa(id))
+	}
+	return err
+}
+
+// IsRedirectedToOtherHost returns true if the resolved URL of the mirror
+// points to a different host than its configured HTTP URL
+func (m *Mirror) IsRedirectedToOtherHost() bool {
+	if m.ResolvedURL == "" {
+		return false
+	}
+	u1, err1 := url.Parse(m.HttpURL)
+	u2, err2 := url.Parse(m.ResolvedURL)
+	if err1 != nil || err2 != nil {
+		return false
+	}
+	return !strings.EqualFold(u1.Hostname(), u2.Hostname())
+}
+
 // MarshalYAML converts internal values to YAML
 func (r Redirects) MarshalYAML() (interface{}, error) {
 	var b *bool
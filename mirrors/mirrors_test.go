@@ -4,8 +4,11 @@
 package mirrors
 
 import (
+	"context"
 	"fmt"
 	"math/rand"
+	"net/http"
+	"net/url"
 	"sort"
 	"strings"
 	"testing"
@@ -710,3 +713,82 @@ func TestSetMirrorState(t *testing.T) {
 		t.Fatalf("Event MIRROR_UPDATE not published")
 	}
 }
+
+func TestIsAllowedRedirectDomain(t *testing.T) {
+	if !IsAllowedRedirectDomain("example.com", nil) {
+		t.Fatalf("An empty list should allow all domains")
+	}
+
+	domains := []string{"example.com", ".cdn.example.org."}
+
+	for _, host := range []string{"example.com", "EXAMPLE.COM", "www.example.com", "a.cdn.example.org", "cdn.example.org."} {
+		if !IsAllowedRedirectDomain(host, domains) {
+			t.Fatalf("%s should be allowed", host)
+		}
+	}
+
+	for _, host := range []string{"badexample.com", "example.org", "example.com.evil.net"} {
+		if IsAllowedRedirectDomain(host, domains) {
+			t.Fatalf("%s should not be allowed", host)
+		}
+	}
+}
+
+func TestResolvedBaseURL(t *testing.T) {
+	u, _ := url.Parse("https://cdn.example.org/mirror/repo/file.iso?token=abc")
+	if r := ResolvedBaseURL(u, "/repo/file.iso"); r != "https://cdn.example.org/mirror/" {
+		t.Fatalf("Unexpected resolved URL: %s", r)
+	}
+
+	u, _ = url.Parse("https://cdn.example.org/other.iso")
+	if r := ResolvedBaseURL(u, "/repo/file.iso"); r != "" {
+		t.Fatalf("Expected an empty string, got %s", r)
+	}
+
+	if r := ResolvedBaseURL(nil, "/repo/file.iso"); r != "" {
+		t.Fatalf("Expected an empty string, got %s", r)
+	}
+}
+
+func TestCheckRedirect(t *testing.T) {
+	newRequest := func(m *Mirror, rawurl string) *http.Request {
+		req, _ := http.NewRequest("GET", rawurl, nil)
+		return req.WithContext(RedirectContext(context.Background(), m))
+	}
+
+	m := &Mirror{ID: 1, Name: "m1", AllowRedirects: 2}
+	origin := newRequest(m, "http://mirror.example.com/file")
+	if err := CheckRedirect(newRequest(m, "http://mirror.example.com/other"), []*http.Request{origin}); err != ErrRedirect {
+		t.Fatalf("Expected ErrRedirect, got %v", err)
+	}
+
+	m = &Mirror{ID: 1, Name: "m1", AllowRedirects: 1}
+	origin = newRequest(m, "http://mirror.example.com/file")
+	if err := CheckRedirect(newRequest(m, "http://cdn.elsewhere.net/file"), []*http.Request{origin}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	m = &Mirror{ID: 1, Name: "m1", AllowRedirects: 1, AllowedRedirectDomains: "example.net"}
+	origin = newRequest(m, "http://mirror.example.com/file")
+	if err := CheckRedirect(newRequest(m, "http://mirror.example.com/other"), []*http.Request{origin}); err != nil {
+		t.Fatalf("Redirect to the same host should be allowed: %v", err)
+	}
+	if err := CheckRedirect(newRequest(m, "http://cdn.example.net/file"), []*http.Request{origin}); err != nil {
+		t.Fatalf("Redirect to an allowed domain should be allowed: %v", err)
+	}
+	if err := CheckRedirect(newRequest(m, "http://cdn.elsewhere.net/file"), []*http.Request{origin}); err != ErrRedirectDomain {
+		t.Fatalf("Expected ErrRedirectDomain, got %v", err)
+	}
+
+	via := make([]*http.Request, maxRedirects)
+	for i := range via {
+		via[i] = origin
+	}
+	if err := CheckRedirect(newRequest(m, "http://mirror.example.com/other"), via); err != ErrTooManyRedirects {
+		t.Fatalf("Expected ErrTooManyRedirects, got %v", err)
+	}
+
+	if !IsRedirectError(fmt.Errorf("Get \"http://x\": %w", ErrRedirectDomain)) {
+		t.Fatalf("IsRedirectError should detect wrapped errors")
+	}
+}
@@ -0,0 +1,156 @@
+// Copyright (c) 2014-2019 Ludovic Fauvet
+// Licensed under the MIT license
+
+package mirrors
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/opensourceways/mirrorbits/database"
+)
+
+var (
+	// ErrInvalidNetwork is returned when a network is not in the CIDR notation
+	ErrInvalidNetwork = errors.New("Invalid network, expected CIDR notation (e.g. 10.0.0.0/8)")
+	// ErrNetworkRuleNotFound is returned when removing an unknown network rule
+	ErrNetworkRuleNotFound = errors.New("Network rule not found")
+)
+
+// NetworkRule routes the clients of a network to a mirror. The matching
+// mirrors are preferred, or used exclusively if the rule is exclusive.
+type NetworkRule struct {
+	Network   string
+	Exclusive bool `json:",omitempty"`
+}
+
+// NetworkRules is a list of network rules stored
+// as a JSON document in the mirror hash
+type NetworkRules []NetworkRule
+
+// RedisArg serializes the network rules
+func (n NetworkRules) RedisArg() interface{} {
+	if len(n) == 0 {
+		return ""
+	}
+	b, _ := json.Marshal([]NetworkRule(n))
+	return b
+}
+
+// RedisScan deserializes the network rules
+func (n *NetworkRules) RedisScan(src interface{}) error {
+	var b []byte
+	switch src := src.(type) {
+	case []byte:
+		b = src
+	case string:
+		b = []byte(src)
+	default:
+		return fmt.Errorf("cannot convert from %T to %T", src, n)
+	}
+	*n = nil
+	if len(b) == 0 {
+		return nil
+	}
+	return json.Unmarshal(b, (*[]NetworkRule)(n))
+}
+
+// ParseNetwork parses a network in the CIDR notation
+func ParseNetwork(network string) (*net.IPNet, error) {
+	_, n, err := net.ParseCIDR(network)
+	if err != nil {
+		return nil, ErrInvalidNetwork
+	}
+	return n, nil
+}
+
+// GetNetworkRules returns the network rules of a mirror
+func GetNetworkRules(r *database.Redis, id int) (NetworkRules, error) {
+	conn := r.Get()
+	defer conn.Close()
+
+	return getNetworkRules(conn, id)
+}
+
+func getNetworkRules(conn redis.Conn, id int) (NetworkRules, error) {
+	var rules NetworkRules
+
+	reply, err := conn.Do("HGET", fmt.Sprintf("MIRROR_%d", id), "networks")
+	if err != nil || reply == nil {
+		return nil, err
+	}
+	err = rules.RedisScan(reply)
+	return rules, err
+}
+
+// updateNetworkRules replaces the network rules of an existing mirror
+// by the ones returned by update given the current ones
+func updateNetworkRules(conn redis.Conn, id int, update func(NetworkRules) (NetworkRules, error)) error {
+	return updateMirrorField(conn, id, "networks", func(reply interface{}) (interface{}, error) {
+		var rules NetworkRules
+		if reply != nil {
+			if err := rules.RedisScan(reply); err != nil {
+				return nil, err
+			}
+		}
+		rules, err := update(rules)
+		if err != nil {
+			return nil, err
+		}
+		return rules.RedisArg(), nil
+	})
+}
+
+// AddNetworkRule routes the clients of the given network to the mirror.
+// An existing rule for the same network is replaced.
+func AddNetworkRule(r *database.Redis, id int, network string, exclusive bool) (NetworkRule, error) {
+	n, err := ParseNetwork(network)
+	if err != nil {
+		return NetworkRule{}, err
+	}
+
+	conn := r.Get()
+	defer conn.Close()
+
+	rule := NetworkRule{
+		Network:   n.String(),
+		Exclusive: exclusive,
+	}
+
+	err = updateNetworkRules(conn, id, func(rules NetworkRules) (NetworkRules, error) {
+		for i := range rules {
+			if rules[i].Network == rule.Network {
+				rules[i] = rule
+				return rules, nil
+			}
+		}
+		return append(rules, rule), nil
+	})
+	if err != nil {
+		return NetworkRule{}, err
+	}
+	return rule, nil
+}
+
+// RemoveNetworkRule removes the rule of the given network from the mirror
+func RemoveNetworkRule(r *database.Redis, id int, network string) error {
+	n, err := ParseNetwork(network)
+	if err != nil {
+		return err
+	}
+
+	conn := r.Get()
+	defer conn.Close()
+
+	return updateNetworkRules(conn, id, func(rules NetworkRules) (NetworkRules, error) {
+		for i, rule := range rules {
+			if rule.Network == n.String() {
+				return append(rules[:i], rules[i+1:]...), nil
+			}
+		}
+		return nil, ErrNetworkRuleNotFound
+	})
+}
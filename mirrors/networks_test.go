@@ -0,0 +1,96 @@
+// Copyright (c) 2014-2019 Ludovic Fauvet
+// Licensed under the MIT license
+
+package mirrors
+
+import (
+	"testing"
+
+	"github.com/opensourceways/mirrorbits/database"
+	. "github.com/opensourceways/mirrorbits/testing"
+	"github.com/rafaeljusto/redigomock"
+)
+
+func TestAddNetworkRule(t *testing.T) {
+	mock, conn := PrepareRedisTest()
+
+	if _, err := AddNetworkRule(conn, 1, "10.0.0.0", false); err != ErrInvalidNetwork {
+		t.Fatalf("Expected ErrInvalidNetwork, got %v", err)
+	}
+
+	existing := NetworkRules{
+		{Network: "10.0.0.0/8"},
+	}
+
+	mockMirrorUpdate(mock, "MIRROR_1")
+	mock.Command("HGET", "MIRROR_1", "networks").Expect(existing.RedisArg())
+	cmdPublish := mock.Command("PUBLISH", string(database.MIRROR_UPDATE), redigomock.NewAnyData()).Expect("ok")
+	cmdSet := mock.Command("HSET", "MIRROR_1", "networks", NetworkRules{
+		{Network: "10.0.0.0/8"},
+		{Network: "2001:db8::/32", Exclusive: true},
+	}.RedisArg()).Expect(int64(0))
+
+	rule, err := AddNetworkRule(conn, 1, "2001:db8:0:1::/32", true)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if rule.Network != "2001:db8::/32" {
+		t.Fatalf("The network must be normalized, got %s", rule.Network)
+	}
+	if mock.Stats(cmdSet) != 1 {
+		t.Fatalf("Rules not saved")
+	}
+	if mock.Stats(cmdPublish) != 1 {
+		t.Fatalf("Event MIRROR_UPDATE not published")
+	}
+}
+
+func TestRemoveNetworkRule(t *testing.T) {
+	mock, conn := PrepareRedisTest()
+
+	existing := NetworkRules{
+		{Network: "10.0.0.0/8"},
+	}
+
+	mockMirrorUpdate(mock, "MIRROR_1")
+	mock.Command("HGET", "MIRROR_1", "networks").Expect(existing.RedisArg())
+	mock.Command("PUBLISH", string(database.MIRROR_UPDATE), redigomock.NewAnyData()).Expect("ok")
+	cmdSet := mock.Command("HSET", "MIRROR_1", "networks", "").Expect(int64(0))
+
+	if err := RemoveNetworkRule(conn, 1, "192.168.0.0/16"); err != ErrNetworkRuleNotFound {
+		t.Fatalf("Expected ErrNetworkRuleNotFound, got %v", err)
+	}
+
+	if err := RemoveNetworkRule(conn, 1, "10.0.0.0/8"); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if mock.Stats(cmdSet) != 1 {
+		t.Fatalf("Rules not saved")
+	}
+}
+
+func TestAddNetworkRule_UnknownMirror(t *testing.T) {
+	m, conn := PrepareMiniredisTest(t)
+
+	if _, err := AddNetworkRule(conn, 2, "10.0.0.0/8", false); err != ErrMirrorNotFound {
+		t.Fatalf("Expected ErrMirrorNotFound, got %v", err)
+	}
+	if err := RemoveNetworkRule(conn, 2, "10.0.0.0/8"); err != ErrMirrorNotFound {
+		t.Fatalf("Expected ErrMirrorNotFound, got %v", err)
+	}
+	if m.Exists("MIRROR_2") {
+		t.Fatalf("The unknown mirror must not be created")
+	}
+
+	m.HSet("MIRROR_1", "ID", "1")
+	if _, err := AddNetworkRule(conn, 1, "10.1.2.3/8", true); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	rules, err := GetNetworkRules(conn, 1)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if len(rules) != 1 || rules[0].Network != "10.0.0.0/8" || !rules[0].Exclusive {
+		t.Fatalf("Unexpected rules: %v", rules)
+	}
+}
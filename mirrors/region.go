@@ -0,0 +1,66 @@
+// Copyright (c) 2014-2019 Ludovic Fauvet
+// Licensed under the MIT license
+
+package mirrors
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/opensourceways/mirrorbits/database"
+	"github.com/opensourceways/mirrorbits/network"
+)
+
+// ApplyRegionAliases rewrites the country codes of the mirror, and its
+// country if the first code is aliased, with the configured region aliases
+func (m *Mirror) ApplyRegionAliases() {
+	if codes := strings.Fields(m.CountryCodes); len(codes) > 0 {
+		if code, country := network.ApplyRegionAlias(codes[0], m.Country); code != codes[0] {
+			m.Country = country
+		}
+	}
+	m.CountryCodes = network.ApplyRegionAliases(m.CountryCodes)
+	m.ExcludedCountryCodes = network.ApplyRegionAliases(m.ExcludedCountryCodes)
+}
+
+// MigrateRegionAliases applies the region aliases to the mirrors registered
+// before they took effect, the new mirrors being rewritten when added
+func MigrateRegionAliases(r *database.Redis) error {
+	conn := r.Get()
+	defer conn.Close()
+
+	ids, err := redis.Ints(conn.Do("HKEYS", "MIRRORS"))
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		key := fmt.Sprintf("MIRROR_%d", id)
+		values, err := redis.Strings(conn.Do("HMGET", key, "country", "countryCodes", "excludedCountryCodes"))
+		if err != nil {
+			return err
+		}
+
+		m := Mirror{
+			Country:              values[0],
+			CountryCodes:         values[1],
+			ExcludedCountryCodes: values[2],
+		}
+		m.ApplyRegionAliases()
+		if m.Country == values[0] && m.CountryCodes == values[1] && m.ExcludedCountryCodes == values[2] {
+			continue
+		}
+
+		_, err = conn.Do("HMSET", key,
+			"country", m.Country,
+			"countryCodes", m.CountryCodes,
+			"excludedCountryCodes", m.ExcludedCountryCodes)
+		if err != nil {
+			return err
+		}
+		database.Publish(conn, database.MIRROR_UPDATE, strconv.Itoa(id))
+	}
+	return nil
+}
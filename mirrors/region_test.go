@@ -0,0 +1,51 @@
+// Copyright (c) 2014-2019 Ludovic Fauvet
+// Licensed under the MIT license
+
+package mirrors
+
+import (
+	"testing"
+
+	. "github.com/opensourceways/mirrorbits/config"
+	. "github.com/opensourceways/mirrorbits/testing"
+)
+
+func TestMigrateRegionAliases(t *testing.T) {
+	SetConfiguration(&Configuration{
+		RegionAliases: []RegionAlias{
+			{Codes: []string{"HK", "MO"}, Code: "CN", Country: "China"},
+		},
+	})
+
+	m, r := PrepareMiniredisTest(t)
+	m.HSet("MIRRORS", "1", "m1", "2", "m2")
+	m.HSet("MIRROR_1",
+		"ID", "1",
+		"country", "Hong Kong",
+		"countryCodes", "HK MO",
+		"excludedCountryCodes", "MO")
+	m.HSet("MIRROR_2",
+		"ID", "2",
+		"country", "France",
+		"countryCodes", "FR HK")
+
+	if err := MigrateRegionAliases(r); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if c := m.HGet("MIRROR_1", "country"); c != "China" {
+		t.Fatalf("Expected China, got %q", c)
+	}
+	if c := m.HGet("MIRROR_1", "countryCodes"); c != "CN" {
+		t.Fatalf("Expected CN, got %q", c)
+	}
+	if c := m.HGet("MIRROR_1", "excludedCountryCodes"); c != "CN" {
+		t.Fatalf("Expected CN, got %q", c)
+	}
+	if c := m.HGet("MIRROR_2", "country"); c != "France" {
+		t.Fatalf("The country must be kept when the first code is not aliased, got %q", c)
+	}
+	if c := m.HGet("MIRROR_2", "countryCodes"); c != "FR CN" {
+		t.Fatalf("Expected \"FR CN\", got %q", c)
+	}
+}
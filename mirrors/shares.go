@@ -0,0 +1,128 @@
+// Copyright (c) 2014-2019 Ludovic Fauvet
+// Licensed under the MIT license
+
+package mirrors
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/opensourceways/mirrorbits/network"
+)
+
+var (
+	// ErrInvalidShareCap is returned when a share cap is not within (0, 100]
+	ErrInvalidShareCap = errors.New("Invalid share cap, expected a percentage within (0, 100]")
+	// ErrAmbiguousShareCap is returned when a share cap targets both a country and a continent
+	ErrAmbiguousShareCap = errors.New("Invalid share cap, Country and Continent are mutually exclusive")
+)
+
+// ShareCap limits the share of the requests redirected to a mirror, among
+// all the requests or those of the clients of a country or a continent
+type ShareCap struct {
+	Share     float32 `yaml:"Share"` // in percent
+	Country   string  `json:",omitempty" yaml:"Country,omitempty"`
+	Continent string  `json:",omitempty" yaml:"Continent,omitempty"`
+}
+
+// Scope returns the set of requests the share is computed on
+func (s ShareCap) Scope() string {
+	switch {
+	case s.Country != "":
+		return "country:" + s.Country
+	case s.Continent != "":
+		return "continent:" + s.Continent
+	}
+	return "all"
+}
+
+// String returns a human readable representation of the share cap
+func (s ShareCap) String() string {
+	switch {
+	case s.Country != "":
+		return fmt.Sprintf("%g%% of %s", s.Share, s.Country)
+	case s.Continent != "":
+		return fmt.Sprintf("%g%% of continent %s", s.Share, s.Continent)
+	}
+	return fmt.Sprintf("%g%% of all", s.Share)
+}
+
+// ShareCaps is a list of share caps stored
+// as a JSON document in the mirror hash
+type ShareCaps []ShareCap
+
+// RedisArg serializes the share caps
+func (s ShareCaps) RedisArg() interface{} {
+	if len(s) == 0 {
+		return ""
+	}
+	b, _ := json.Marshal([]ShareCap(s))
+	return b
+}
+
+// RedisScan deserializes the share caps
+func (s *ShareCaps) RedisScan(src interface{}) error {
+	var b []byte
+	switch src := src.(type) {
+	case []byte:
+		b = src
+	case string:
+		b = []byte(src)
+	default:
+		return fmt.Errorf("cannot convert from %T to %T", src, s)
+	}
+	*s = nil
+	if len(b) == 0 {
+		return nil
+	}
+	return json.Unmarshal(b, (*[]ShareCap)(s))
+}
+
+// Normalize validates the share caps and uppercases their location codes
+func (s ShareCaps) Normalize() error {
+	for i := range s {
+		if s[i].Share <= 0 || s[i].Share > 100 {
+			return ErrInvalidShareCap
+		}
+		if s[i].Country != "" && s[i].Continent != "" {
+			return ErrAmbiguousShareCap
+		}
+		s[i].Country = strings.ToUpper(s[i].Country)
+		s[i].Continent = strings.ToUpper(s[i].Continent)
+	}
+	return nil
+}
+
+// Applies returns true if the requests of the client count in the share
+func (s ShareCap) Applies(clientInfo network.GeoIPRecord) bool {
+	switch {
+	case s.Country != "":
+		return clientInfo.IsValid() && clientInfo.CountryCode == s.Country
+	case s.Continent != "":
+		return clientInfo.IsValid() && clientInfo.ContinentCode == s.Continent
+	}
+	return true
+}
+
+// ShareScopes returns the scopes of the share caps the requests of the client count in
+func ShareScopes(clientInfo network.GeoIPRecord) []string {
+	scopes := []string{"all"}
+	if clientInfo.IsValid() {
+		if clientInfo.CountryCode != "" {
+			scopes = append(scopes, ShareCap{Country: clientInfo.CountryCode}.Scope())
+		}
+		if clientInfo.ContinentCode != "" {
+			scopes = append(scopes, ShareCap{Continent: clientInfo.ContinentCode}.Scope())
+		}
+	}
+	return scopes
+}
+
+// ShareUsage is the state of a share cap of a mirror
+type ShareUsage struct {
+	Cap    string
+	Share  float32 // in percent of the requests of the scope
+	Capped bool
+}
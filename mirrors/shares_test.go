@@ -0,0 +1,60 @@
+// Copyright (c) 2014-2019 Ludovic Fauvet
+// Licensed under the MIT license
+
+package mirrors
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/opensourceways/mirrorbits/network"
+)
+
+func TestShareCaps_Normalize(t *testing.T) {
+	caps := ShareCaps{{Share: 20, Country: "cn"}, {Share: 50, Continent: "as"}, {Share: 100}}
+	if err := caps.Normalize(); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if caps[0].Country != "CN" || caps[1].Continent != "AS" {
+		t.Fatalf("Location codes must be uppercased, got %+v", caps)
+	}
+	if caps[0].Scope() != "country:CN" || caps[1].Scope() != "continent:AS" || caps[2].Scope() != "all" {
+		t.Fatalf("Unexpected scopes %s, %s, %s", caps[0].Scope(), caps[1].Scope(), caps[2].Scope())
+	}
+
+	if err := (ShareCaps{{Share: 0}}).Normalize(); err != ErrInvalidShareCap {
+		t.Fatalf("Expected ErrInvalidShareCap, got %v", err)
+	}
+	if err := (ShareCaps{{Share: 101}}).Normalize(); err != ErrInvalidShareCap {
+		t.Fatalf("Expected ErrInvalidShareCap, got %v", err)
+	}
+	if err := (ShareCaps{{Share: 10, Country: "CN", Continent: "AS"}}).Normalize(); err != ErrAmbiguousShareCap {
+		t.Fatalf("Expected ErrAmbiguousShareCap, got %v", err)
+	}
+}
+
+func TestShareCaps_Redis(t *testing.T) {
+	caps := ShareCaps{{Share: 20, Country: "CN"}}
+
+	var scanned ShareCaps
+	if err := scanned.RedisScan(caps.RedisArg()); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if !reflect.DeepEqual(caps, scanned) {
+		t.Fatalf("Expected %+v, got %+v", caps, scanned)
+	}
+
+	if err := scanned.RedisScan([]byte("")); err != nil || scanned != nil {
+		t.Fatalf("Expected no share cap, got %+v (%v)", scanned, err)
+	}
+}
+
+func TestShareScopes(t *testing.T) {
+	scopes := ShareScopes(network.GeoIPRecord{CountryCode: "CN", ContinentCode: "AS"})
+	if !reflect.DeepEqual(scopes, []string{"all", "country:CN", "continent:AS"}) {
+		t.Fatalf("Unexpected scopes %v", scopes)
+	}
+	if scopes := ShareScopes(network.GeoIPRecord{}); !reflect.DeepEqual(scopes, []string{"all"}) {
+		t.Fatalf("Unexpected scopes %v", scopes)
+	}
+}
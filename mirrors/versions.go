@@ -0,0 +1,93 @@
+// Copyright (c) 2014-2019 Ludovic Fauvet
+// Licensed under the MIT license
+
+package mirrors
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+
+	"github.com/opensourceways/mirrorbits/database"
+)
+
+// VersionAvailability maps each published version of the repository to its
+// availability on a mirror, as probed by the health checks
+type VersionAvailability map[string]bool
+
+// RedisArg serializes the availability map
+func (v VersionAvailability) RedisArg() interface{} {
+	if len(v) == 0 {
+		return ""
+	}
+	b, _ := json.Marshal(map[string]bool(v))
+	return b
+}
+
+// RedisScan deserializes the availability map
+func (v *VersionAvailability) RedisScan(src interface{}) error {
+	var b []byte
+	switch src := src.(type) {
+	case []byte:
+		b = src
+	case string:
+		b = []byte(src)
+	default:
+		return fmt.Errorf("cannot convert from %T to %T", src, v)
+	}
+	*v = nil
+	if len(b) == 0 {
+		return nil
+	}
+	return json.Unmarshal(b, (*map[string]bool)(v))
+}
+
+// Missing returns the sorted list of versions known to be unavailable
+func (v VersionAvailability) Missing() []string {
+	var missing []string
+	for version, available := range v {
+		if !available {
+			missing = append(missing, version)
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
+
+// HasVersion returns false only if the given version is known to be
+// unavailable on the mirror. Versions never probed are assumed available.
+func (m *Mirror) HasVersion(version string) bool {
+	available, known := m.Versions[version]
+	return !known || available
+}
+
+// SetMirrorVersions records the availability of the versions on the given mirror
+func SetMirrorVersions(r *database.Redis, id int, versions VersionAvailability) error {
+	conn := r.Get()
+	defer conn.Close()
+
+	key := fmt.Sprintf("MIRROR_%d", id)
+
+	var previous VersionAvailability
+	reply, err := conn.Do("HGET", key, "versions")
+	if err != nil {
+		return err
+	}
+	if reply != nil {
+		if err := previous.RedisScan(reply); err != nil {
+			return err
+		}
+	}
+	if len(previous) == len(versions) && (len(versions) == 0 || reflect.DeepEqual(previous, versions)) {
+		return nil
+	}
+
+	_, err = conn.Do("HSET", key, "versions", versions.RedisArg())
+	if err == nil {
+		// Publish update
+		database.Publish(conn, database.MIRROR_UPDATE, strconv.Itoa(id))
+	}
+	return err
+}
@@ -0,0 +1,68 @@
+// Copyright (c) 2014-2019 Ludovic Fauvet
+// Licensed under the MIT license
+
+package mirrors
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/opensourceways/mirrorbits/database"
+	. "github.com/opensourceways/mirrorbits/testing"
+	"github.com/rafaeljusto/redigomock"
+)
+
+func TestMirror_HasVersion(t *testing.T) {
+	m := Mirror{
+		Versions: VersionAvailability{
+			"openEuler-22.03-LTS": true,
+			"openEuler-24.03-LTS": false,
+		},
+	}
+
+	if !m.HasVersion("openEuler-22.03-LTS") {
+		t.Fatalf("openEuler-22.03-LTS should be available")
+	}
+	if m.HasVersion("openEuler-24.03-LTS") {
+		t.Fatalf("openEuler-24.03-LTS should not be available")
+	}
+	if !m.HasVersion("openEuler-20.03-LTS") {
+		t.Fatalf("Unknown versions should be considered available")
+	}
+	if missing := m.Versions.Missing(); !reflect.DeepEqual(missing, []string{"openEuler-24.03-LTS"}) {
+		t.Fatalf("Unexpected missing versions: %v", missing)
+	}
+}
+
+func TestSetMirrorVersions(t *testing.T) {
+	mock, conn := PrepareRedisTest()
+
+	versions := VersionAvailability{
+		"openEuler-22.03-LTS": true,
+		"openEuler-24.03-LTS": false,
+	}
+
+	cmdGet := mock.Command("HGET", "MIRROR_1", "versions").Expect(versions.RedisArg())
+	cmdSet := mock.Command("HSET", "MIRROR_1", "versions", redigomock.NewAnyData()).Expect(int64(0))
+	cmdPublish := mock.Command("PUBLISH", string(database.MIRROR_UPDATE), redigomock.NewAnyData()).Expect("ok")
+
+	if err := SetMirrorVersions(conn, 1, VersionAvailability{"openEuler-22.03-LTS": true, "openEuler-24.03-LTS": false}); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if mock.Stats(cmdGet) != 1 {
+		t.Fatalf("Previous versions not fetched")
+	}
+	if mock.Stats(cmdSet) != 0 || mock.Stats(cmdPublish) != 0 {
+		t.Fatalf("Unchanged versions must not be saved")
+	}
+
+	if err := SetMirrorVersions(conn, 1, VersionAvailability{"openEuler-22.03-LTS": true, "openEuler-24.03-LTS": true}); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if mock.Stats(cmdSet) != 1 {
+		t.Fatalf("Versions not saved")
+	}
+	if mock.Stats(cmdPublish) != 1 {
+		t.Fatalf("Event MIRROR_UPDATE not published")
+	}
+}
@@ -0,0 +1,38 @@
+// Copyright (c) 2014-2019 Ludovic Fauvet
+// Licensed under the MIT license
+
+package network
+
+import (
+	. "github.com/opensourceways/mirrorbits/config"
+)
+
+// ASNGroupOf returns the name of the ASN group (see ASNGroups) the AS
+// number belongs to, or an empty string if it doesn't belong to any
+func ASNGroupOf(asnum uint) string {
+	if asnum == 0 {
+		return ""
+	}
+	for _, g := range GetConfig().ASNGroups {
+		for _, n := range g.ASNums {
+			if n == asnum {
+				return g.Name
+			}
+		}
+	}
+	return ""
+}
+
+// IsExclusiveASNGroup returns true if the clients of the given ASN group
+// must only be served by the mirrors of the group
+func IsExclusiveASNGroup(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, g := range GetConfig().ASNGroups {
+		if g.Name == name {
+			return g.Exclusive
+		}
+	}
+	return false
+}
@@ -0,0 +1,36 @@
+// Copyright (c) 2014-2019 Ludovic Fauvet
+// Licensed under the MIT license
+
+package network
+
+import (
+	"testing"
+
+	. "github.com/opensourceways/mirrorbits/config"
+)
+
+func TestASNGroupOf(t *testing.T) {
+	SetConfiguration(&Configuration{
+		ASNGroups: []ASNGroup{
+			{Name: "telecom", ASNums: []uint{4134, 4809}, Exclusive: true},
+			{Name: "unicom", ASNums: []uint{4837}},
+		},
+	})
+
+	if g := ASNGroupOf(4809); g != "telecom" {
+		t.Fatalf("Expected telecom, got %q", g)
+	}
+	if g := ASNGroupOf(4837); g != "unicom" {
+		t.Fatalf("Expected unicom, got %q", g)
+	}
+	if g := ASNGroupOf(1234); g != "" {
+		t.Fatalf("Expected no group, got %q", g)
+	}
+	if g := ASNGroupOf(0); g != "" {
+		t.Fatalf("Expected no group, got %q", g)
+	}
+
+	if !IsExclusiveASNGroup("telecom") || IsExclusiveASNGroup("unicom") || IsExclusiveASNGroup("") {
+		t.Fatalf("Only telecom is exclusive")
+	}
+}
@@ -42,12 +42,20 @@ type GeoIPRecord struct {
 	ContinentCode string
 	City          string
 	Country       string
-	Latitude      float32
-	Longitude     float32
+	// SubdivisionCode is the ISO 3166-2 code of the largest
+	// subdivision of the country (e.g. CN-GD), if known
+	SubdivisionCode string `json:",omitempty"`
+	Latitude        float32
+	Longitude       float32
 
 	// ASN DB
 	ASName string
 	ASNum  uint
+	// ASNGroup is the name of the ASN group of the client, if any
+	ASNGroup string `json:",omitempty"`
+
+	// Address of the client
+	IP net.IP `json:"-"`
 }
 
 // Geolocalizer is an interface representing a GeoIP library
@@ -143,6 +151,13 @@ func (g *GeoIP) LoadGeoIP() error {
 	return nil
 }
 
+// Loaded returns whether the city and ASN databases are loaded
+func (g *GeoIP) Loaded() (city, asn bool) {
+	g.RLock()
+	defer g.RUnlock()
+	return g.city != nil && g.city.db != nil, g.asn != nil && g.asn.db != nil
+}
+
 // GetRecord return informations about the given ip address
 // (works in IPv4 and v6)
 func (g *GeoIP) GetRecord(ip string) (ret GeoIPRecord) {
@@ -151,6 +166,7 @@ func (g *GeoIP) GetRecord(ip string) (ret GeoIPRecord) {
 	if addr == nil {
 		return GeoIPRecord{}
 	}
+	ret.IP = addr
 
 	type CityDb struct {
 		City struct {
@@ -167,6 +183,9 @@ func (g *GeoIP) GetRecord(ip string) (ret GeoIPRecord) {
 		Continent struct {
 			Code string `maxminddb:"code"`
 		} `maxminddb:"continent"`
+		Subdivisions []struct {
+			IsoCode string `maxminddb:"iso_code"`
+		} `maxminddb:"subdivisions"`
 		Location struct {
 			Latitude  float64 `maxminddb:"latitude"`
 			Longitude float64 `maxminddb:"longitude"`
@@ -188,38 +207,25 @@ func (g *GeoIP) GetRecord(ip string) (ret GeoIPRecord) {
 	if g.city != nil && g.city.db != nil {
 		err = g.city.db.Lookup(addr, &cityDb)
 		if err != nil {
-			return GeoIPRecord{}
-		}
-		// Country code becomes country
-		//ret.CountryCode = cityDb.Country.IsoCode
-		isoCode := strings.ToUpper(cityDb.Country.IsoCode)
-		if isoCode == "TWN" || isoCode == "TPE" || isoCode == "TW"{
-			ret.CountryCode = "CN"
-			ret.Country = "China"
-		} else if isoCode == "HK" ||
-			isoCode == "HKSAR" || isoCode == "HKG" {
-			ret.CountryCode = "CN"
-			ret.Country = "China"
-		} else if isoCode == "MO" ||
-			isoCode == "MC" || isoCode == "OMA" {
-			ret.CountryCode = "CN"
-			ret.Country = "China"
-		} else {
-			ret.CountryCode = isoCode
-			ret.Country = cityDb.Country.Names.English
+			return GeoIPRecord{IP: addr}
 		}
+		ret.CountryCode, ret.Country = ApplyRegionAlias(cityDb.Country.IsoCode, cityDb.Country.Names.English)
 		ret.ContinentCode = cityDb.Continent.Code
 		ret.City = cityDb.City.Names.English
+		if len(cityDb.Subdivisions) > 0 && cityDb.Subdivisions[0].IsoCode != "" {
+			ret.SubdivisionCode = strings.ToUpper(cityDb.Country.IsoCode + "-" + cityDb.Subdivisions[0].IsoCode)
+		}
 		ret.Latitude = float32(cityDb.Location.Latitude)
 		ret.Longitude = float32(cityDb.Location.Longitude)
 	}
 	if g.asn != nil && g.asn.db != nil {
 		err = g.asn.db.Lookup(addr, &asnDb)
 		if err != nil {
-			return GeoIPRecord{}
+			return GeoIPRecord{IP: addr}
 		}
 		ret.ASName = asnDb.AutonomousSystemOrg
 		ret.ASNum = asnDb.AutonomousSystemNumber
+		ret.ASNGroup = ASNGroupOf(ret.ASNum)
 	}
 
 	return ret
@@ -9,6 +9,8 @@ import (
 	"strings"
 	"testing"
 	"time"
+
+	. "github.com/opensourceways/mirrorbits/config"
 )
 
 type CityDb struct {
@@ -45,6 +47,8 @@ func TestNewGeoIP(t *testing.T) {
 }
 
 func TestGeoIP_GetRecord(t *testing.T) {
+	SetConfiguration(&Configuration{})
+
 	g := NewGeoIP()
 
 	mockcity := &geoipDB{
@@ -90,6 +94,34 @@ func TestGeoIP_GetRecord(t *testing.T) {
 	}
 }
 
+func TestGeoIP_GetRecordRegionAlias(t *testing.T) {
+	SetConfiguration(&Configuration{
+		RegionAliases: []RegionAlias{
+			{Codes: []string{"TEST2"}, Code: "CN", Country: "China"},
+		},
+	})
+
+	g := NewGeoIP()
+	g.city = &geoipDB{
+		filename: "city.mmdb",
+		modTime:  time.Now(),
+		db:       &GeoIPMockCity{},
+	}
+	g.asn = &geoipDB{
+		filename: "asn.mmdb",
+		modTime:  time.Now(),
+		db:       &GeoIPMockASN{},
+	}
+
+	r := g.GetRecord("127.0.0.1")
+	if r.CountryCode != "CN" {
+		t.Fatalf("Invalid response got %s, expected CN", r.CountryCode)
+	}
+	if r.Country != "China" {
+		t.Fatalf("Invalid response got %s, expected China", r.Country)
+	}
+}
+
 func TestIsIPv6(t *testing.T) {
 	g := NewGeoIP()
 	if g.IsIPv6("192.168.0.1") == true {
@@ -0,0 +1,48 @@
+// Copyright (c) 2014-2019 Ludovic Fauvet
+// Licensed under the MIT license
+
+package network
+
+import (
+	"strings"
+
+	. "github.com/opensourceways/mirrorbits/config"
+)
+
+// ApplyRegionAlias returns the country code and display name the given
+// country code is aliased to (see RegionAliases), or the given values
+// if there's no alias for it.
+func ApplyRegionAlias(code, country string) (string, string) {
+	code = strings.ToUpper(code)
+	for _, a := range GetConfig().RegionAliases {
+		for _, c := range a.Codes {
+			if c == code {
+				if a.Country != "" {
+					country = a.Country
+				}
+				return a.Code, country
+			}
+		}
+	}
+	return code, country
+}
+
+// ApplyRegionAliases applies the region aliases to a space separated list
+// of country codes, removing the duplicates
+func ApplyRegionAliases(codes string) string {
+	var ret []string
+	for _, code := range strings.Fields(codes) {
+		code, _ = ApplyRegionAlias(code, "")
+		found := false
+		for _, c := range ret {
+			if c == code {
+				found = true
+				break
+			}
+		}
+		if !found {
+			ret = append(ret, code)
+		}
+	}
+	return strings.Join(ret, " ")
+}
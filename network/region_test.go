@@ -0,0 +1,44 @@
+// Copyright (c) 2014-2019 Ludovic Fauvet
+// Licensed under the MIT license
+
+package network
+
+import (
+	"testing"
+
+	. "github.com/opensourceways/mirrorbits/config"
+)
+
+func TestApplyRegionAlias(t *testing.T) {
+	SetConfiguration(&Configuration{
+		RegionAliases: []RegionAlias{
+			{Codes: []string{"HK", "HKG"}, Code: "CN", Country: "China"},
+			{Codes: []string{"GF"}, Code: "FR"},
+		},
+	})
+
+	if code, country := ApplyRegionAlias("hkg", "Hong Kong"); code != "CN" || country != "China" {
+		t.Fatalf("Expected CN/China, got %s/%s", code, country)
+	}
+	if code, country := ApplyRegionAlias("GF", "French Guiana"); code != "FR" || country != "French Guiana" {
+		t.Fatalf("The country name must be kept when the alias has none, got %s/%s", code, country)
+	}
+	if code, country := ApplyRegionAlias("de", "Germany"); code != "DE" || country != "Germany" {
+		t.Fatalf("Expected DE/Germany, got %s/%s", code, country)
+	}
+}
+
+func TestApplyRegionAliases(t *testing.T) {
+	SetConfiguration(&Configuration{
+		RegionAliases: []RegionAlias{
+			{Codes: []string{"HK", "MO"}, Code: "CN"},
+		},
+	})
+
+	if codes := ApplyRegionAliases("CN HK MO DE"); codes != "CN DE" {
+		t.Fatalf("Expected \"CN DE\", got %q", codes)
+	}
+	if codes := ApplyRegionAliases(""); codes != "" {
+		t.Fatalf("Expected an empty list, got %q", codes)
+	}
+}
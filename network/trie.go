@@ -0,0 +1,79 @@
+// Copyright (c) 2014-2019 Ludovic Fauvet
+// Licensed under the MIT license
+
+package network
+
+import (
+	"net"
+)
+
+// Trie is a binary prefix trie used to find the networks containing an address
+type Trie struct {
+	v4 *trieNode
+	v6 *trieNode
+}
+
+type trieNode struct {
+	children [2]*trieNode
+	values   []interface{}
+}
+
+// NewTrie returns an empty trie
+func NewTrie() *Trie {
+	return &Trie{
+		v4: &trieNode{},
+		v6: &trieNode{},
+	}
+}
+
+// Insert associates the value to the given network
+func (t *Trie) Insert(n *net.IPNet, value interface{}) {
+	node := t.v6
+	ip := n.IP.To16()
+	if ip4 := n.IP.To4(); ip4 != nil {
+		node = t.v4
+		ip = ip4
+	}
+	ones, bits := n.Mask.Size()
+	if bits == 128 && len(ip) == net.IPv4len {
+		// IPv4-mapped network given with a 128 bits mask
+		ones -= 96
+	}
+
+	for i := 0; i < ones; i++ {
+		bit := (ip[i/8] >> uint(7-i%8)) & 1
+		if node.children[bit] == nil {
+			node.children[bit] = &trieNode{}
+		}
+		node = node.children[bit]
+	}
+	node.values = append(node.values, value)
+}
+
+// Lookup returns the values of all the networks containing the address,
+// ordered from the least to the most specific network
+func (t *Trie) Lookup(ip net.IP) []interface{} {
+	if t == nil || ip == nil {
+		return nil
+	}
+	node := t.v6
+	addr := ip.To16()
+	if ip4 := ip.To4(); ip4 != nil {
+		node = t.v4
+		addr = ip4
+	}
+	if addr == nil {
+		return nil
+	}
+
+	var values []interface{}
+	for i := 0; node != nil; i++ {
+		values = append(values, node.values...)
+		if i == len(addr)*8 {
+			break
+		}
+		bit := (addr[i/8] >> uint(7-i%8)) & 1
+		node = node.children[bit]
+	}
+	return values
+}
@@ -0,0 +1,45 @@
+// Copyright (c) 2014-2019 Ludovic Fauvet
+// Licensed under the MIT license
+
+package network
+
+import (
+	"net"
+	"reflect"
+	"testing"
+)
+
+func TestTrie_Lookup(t *testing.T) {
+	trie := NewTrie()
+
+	for _, n := range []string{"10.0.0.0/8", "10.1.0.0/16", "192.168.1.0/24", "2001:db8::/32", "0.0.0.0/0"} {
+		_, network, err := net.ParseCIDR(n)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		trie.Insert(network, n)
+	}
+
+	tests := []struct {
+		ip       string
+		expected []interface{}
+	}{
+		{"10.1.2.3", []interface{}{"0.0.0.0/0", "10.0.0.0/8", "10.1.0.0/16"}},
+		{"10.2.2.3", []interface{}{"0.0.0.0/0", "10.0.0.0/8"}},
+		{"192.168.2.1", []interface{}{"0.0.0.0/0"}},
+		{"::ffff:192.168.1.20", []interface{}{"0.0.0.0/0", "192.168.1.0/24"}},
+		{"2001:db8:1::1", []interface{}{"2001:db8::/32"}},
+		{"2001:db9::1", nil},
+	}
+
+	for _, test := range tests {
+		values := trie.Lookup(net.ParseIP(test.ip))
+		if !reflect.DeepEqual(values, test.expected) {
+			t.Fatalf("%s: expected %v, got %v", test.ip, test.expected, values)
+		}
+	}
+
+	if values := trie.Lookup(nil); values != nil {
+		t.Fatalf("Expected no match for a nil address, got %v", values)
+	}
+}
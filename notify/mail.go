@@ -0,0 +1,196 @@
+// Copyright (c) 2014-2019 Ludovic Fauvet
+// Licensed under the MIT license
+
+package notify
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	. "github.com/opensourceways/mirrorbits/config"
+	"github.com/opensourceways/mirrorbits/mirrors"
+)
+
+const (
+	// outagesKey maps the ID of the mirrors currently down to the start of their outage
+	outagesKey = "NOTIFY_OUTAGES"
+	// mailedKey is the set of mirrors whose admin has been warned about the current outage
+	mailedKey = "NOTIFY_OUTAGES_MAILED"
+)
+
+// trackOutage records the start and the end of the mirror outages
+func (n *Notifier) trackOutage(action mirrors.LogAction) error {
+	state, ok := action.(*mirrors.LogStateChanged)
+	if !ok || GetConfig().Notifications.SMTP.Address == "" {
+		return nil
+	}
+
+	conn := n.redis.Get()
+	defer conn.Close()
+
+	id := strconv.Itoa(state.MirrorID)
+
+	if !state.Up {
+		_, err := conn.Do("HSETNX", outagesKey, id, state.Timestamp.Unix())
+		return err
+	}
+
+	if _, err := conn.Do("HDEL", outagesKey, id); err != nil {
+		return err
+	}
+	mailed, err := redis.Int(conn.Do("SREM", mailedKey, id))
+	if err != nil || mailed == 0 {
+		return err
+	}
+
+	n.wg.Add(1)
+	go func() {
+		defer n.wg.Done()
+		mirror, err := n.getMirror(state.MirrorID)
+		if err != nil || mirror == nil || mirror.AdminEmail == "" {
+			return
+		}
+		if err := SendMail(GetConfig().Notifications.SMTP, mirror.AdminEmail, recoveryMail(mirror)); err != nil {
+			log.Errorf("Notification: unable to send the recovery email of %s: %s", mirror.Name, err)
+		}
+	}()
+	return nil
+}
+
+// checkOutages emails the admin of the mirrors down for longer than the configured threshold
+func (n *Notifier) checkOutages() error {
+	cfg := GetConfig().Notifications.SMTP
+	if cfg.Address == "" {
+		return nil
+	}
+
+	conn := n.redis.Get()
+	defer conn.Close()
+
+	outages, err := redis.Int64Map(conn.Do("HGETALL", outagesKey))
+	if err != nil {
+		return err
+	}
+
+	threshold := time.Now().Add(-time.Duration(cfg.OutageThreshold) * time.Minute).Unix()
+
+	for id, since := range outages {
+		if since > threshold {
+			continue
+		}
+
+		// Only one node of the cluster will be able to claim the outage
+		claimed, err := redis.Int(conn.Do("HDEL", outagesKey, id))
+		if err != nil {
+			return err
+		}
+		if claimed == 0 {
+			continue
+		}
+
+		mirrorID, _ := strconv.Atoi(id)
+		mirror, err := n.getMirror(mirrorID)
+		if err != nil {
+			conn.Do("HSETNX", outagesKey, id, since)
+			return err
+		}
+		if mirror == nil || mirror.Up {
+			continue
+		}
+		if _, err := conn.Do("SADD", mailedKey, id); err != nil {
+			return err
+		}
+		if mirror.AdminEmail == "" {
+			continue
+		}
+
+		err = SendMail(cfg, mirror.AdminEmail, outageMail(mirror, time.Unix(since, 0)))
+		if err != nil {
+			log.Errorf("Notification: unable to send the outage email of %s: %s", mirror.Name, err)
+			// Try again on the next check
+			conn.Do("SREM", mailedKey, id)
+			conn.Do("HSETNX", outagesKey, id, since)
+		}
+	}
+	return nil
+}
+
+func (n *Notifier) getMirror(id int) (*mirrors.Mirror, error) {
+	conn := n.redis.Get()
+	defer conn.Close()
+
+	m, err := redis.Values(conn.Do("HGETALL", fmt.Sprintf("MIRROR_%d", id)))
+	if err != nil {
+		return nil, err
+	}
+	if len(m) == 0 {
+		return nil, nil
+	}
+
+	var mirror mirrors.Mirror
+	if err := redis.ScanStruct(m, &mirror); err != nil {
+		return nil, err
+	}
+	return &mirror, nil
+}
+
+// Mail is a plain text email
+type Mail struct {
+	Subject string
+	Body    string
+}
+
+func outageMail(m *mirrors.Mirror, since time.Time) Mail {
+	var body bytes.Buffer
+	fmt.Fprintf(&body, "Hello %s,\n\n", adminName(m))
+	fmt.Fprintf(&body, "The mirror %s (%s) is down since %s (%s).\n",
+		m.Name, m.HttpURL, since.Format(time.RFC1123), time.Since(since).Truncate(time.Minute))
+	if m.ExcludeReason != "" {
+		fmt.Fprintf(&body, "Reason: %s\n", m.ExcludeReason)
+	}
+	fmt.Fprintf(&body, "\nRequests are redirected to other mirrors until it is available again.\n")
+	return Mail{
+		Subject: fmt.Sprintf("Mirror %s is down", m.Name),
+		Body:    body.String(),
+	}
+}
+
+func recoveryMail(m *mirrors.Mirror) Mail {
+	return Mail{
+		Subject: fmt.Sprintf("Mirror %s is up", m.Name),
+		Body:    fmt.Sprintf("Hello %s,\n\nThe mirror %s (%s) is available again.\n", adminName(m), m.Name, m.HttpURL),
+	}
+}
+
+func adminName(m *mirrors.Mirror) string {
+	if m.AdminName != "" {
+		return m.AdminName
+	}
+	return "admin"
+}
+
+// SendMail sends the mail to the recipient using the given SMTP server
+func SendMail(cfg SMTPServer, to string, mail Mail) error {
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", cfg.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", to)
+	fmt.Fprintf(&msg, "Subject: [mirrorbits] %s\r\n", mail.Subject)
+	fmt.Fprintf(&msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&msg, "Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	msg.Write(bytes.Replace([]byte(mail.Body), []byte("\n"), []byte("\r\n"), -1))
+
+	var auth smtp.Auth
+	if cfg.Username != "" {
+		host, _, err := net.SplitHostPort(cfg.Address)
+		if err != nil {
+			return err
+		}
+		auth = smtp.PlainAuth("", cfg.Username, cfg.Password, host)
+	}
+	return smtp.SendMail(cfg.Address, auth, cfg.From, []string{to}, msg.Bytes())
+}
@@ -0,0 +1,354 @@
+// Copyright (c) 2014-2019 Ludovic Fauvet
+// Licensed under the MIT license
+
+package notify
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/op/go-logging"
+	. "github.com/opensourceways/mirrorbits/config"
+	"github.com/opensourceways/mirrorbits/core"
+	"github.com/opensourceways/mirrorbits/database"
+	"github.com/opensourceways/mirrorbits/mirrors"
+)
+
+const (
+	// retryQueueKey is a sorted set of pending deliveries scored by their next attempt
+	retryQueueKey = "NOTIFY_QUEUE"
+
+	// SignatureHeader contains the HMAC-SHA256 of the body computed with the webhook secret
+	SignatureHeader = "X-Mirrorbits-Signature"
+	// EventHeader contains the type of the event
+	EventHeader = "X-Mirrorbits-Event"
+	// DeliveryHeader contains the unique identifier of the delivery
+	DeliveryHeader = "X-Mirrorbits-Delivery"
+
+	maxRetryDelay = 6 * time.Hour
+
+	// eventQueueSize is the number of mirror events waiting to be processed
+	// before the new ones are dropped
+	eventQueueSize = 1024
+)
+
+var (
+	log = logging.MustGetLogger("main")
+)
+
+// Event is the JSON document sent to the webhooks
+type Event struct {
+	Event      string            `json:"event"`
+	MirrorID   int               `json:"mirrorId"`
+	MirrorName string            `json:"mirrorName"`
+	Timestamp  time.Time         `json:"timestamp"`
+	Message    string            `json:"message"`
+	Data       mirrors.LogAction `json:"data"`
+}
+
+// delivery is a pending POST of an event to a webhook, as stored in the retry queue
+type delivery struct {
+	ID       string
+	URL      string
+	Event    string
+	Payload  json.RawMessage
+	Attempts int
+}
+
+// Notifier sends the mirror events to the configured webhooks and
+// warns the mirror administrators by email about prolonged outages
+type Notifier struct {
+	redis    *database.Redis
+	client   *http.Client
+	events   chan mirrors.LogAction
+	wakeup   chan struct{}
+	stop     chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+}
+
+// NewNotifier returns a new notifier listening to the mirror logs
+func NewNotifier(r *database.Redis) *Notifier {
+	n := &Notifier{
+		redis: r,
+		client: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+		events: make(chan mirrors.LogAction, eventQueueSize),
+		wakeup: make(chan struct{}, 1),
+		stop:   make(chan struct{}),
+	}
+	mirrors.AddLogHook(n.logHook)
+	n.wg.Add(2)
+	go n.eventLoop()
+	go n.loop()
+	return n
+}
+
+// Stop terminates the delivery loop, pending deliveries are kept in the queue
+// while the events not processed yet are lost
+func (n *Notifier) Stop() {
+	n.stopOnce.Do(func() {
+		close(n.stop)
+	})
+	n.wg.Wait()
+}
+
+// logHook hands the event over to the event loop without blocking the
+// caller of PushLog, the event being dropped if the loop lags behind
+func (n *Notifier) logHook(action mirrors.LogAction) {
+	select {
+	case n.events <- action:
+	default:
+		log.Warningf("Notification: event queue full, dropping %s event of mirror %d", action.GetType(), action.GetMirrorID())
+	}
+}
+
+// eventLoop processes the events of the mirrors in their order of arrival
+func (n *Notifier) eventLoop() {
+	defer n.wg.Done()
+
+	for {
+		select {
+		case <-n.stop:
+			return
+		case action := <-n.events:
+			n.processEvent(action)
+		}
+	}
+}
+
+func (n *Notifier) processEvent(action mirrors.LogAction) {
+	if err := n.enqueueEvent(action); err != nil {
+		log.Errorf("Notification: unable to queue event: %s", err)
+	}
+	if err := n.trackOutage(action); err != nil {
+		log.Errorf("Notification: unable to track outage: %s", err)
+	}
+}
+
+// enqueueEvent adds a delivery of the event to each webhook subscribed to it
+func (n *Notifier) enqueueEvent(action mirrors.LogAction) error {
+	webhooks := subscribedWebhooks(GetConfig().Notifications.Webhooks, action.GetType())
+	if len(webhooks) == 0 {
+		return nil
+	}
+
+	conn := n.redis.Get()
+	defer conn.Close()
+
+	name, err := redis.String(conn.Do("HGET", fmt.Sprintf("MIRROR_%d", action.GetMirrorID()), "name"))
+	if err != nil && err != redis.ErrNil {
+		return err
+	}
+
+	payload, err := json.Marshal(Event{
+		Event:      action.GetType().String(),
+		MirrorID:   action.GetMirrorID(),
+		MirrorName: name,
+		Timestamp:  action.GetTimestamp(),
+		Message:    action.GetOutput(),
+		Data:       action,
+	})
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, w := range webhooks {
+		d := &delivery{
+			ID:      newDeliveryID(),
+			URL:     w.URL,
+			Event:   action.GetType().String(),
+			Payload: payload,
+		}
+		if err := scheduleDelivery(conn, d, now); err != nil {
+			return err
+		}
+	}
+
+	select {
+	case n.wakeup <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+func subscribedWebhooks(webhooks []Webhook, typ mirrors.LogType) []Webhook {
+	var subscribed []Webhook
+	for _, w := range webhooks {
+		if len(w.Events) == 0 {
+			subscribed = append(subscribed, w)
+			continue
+		}
+		for _, e := range w.Events {
+			if t, ok := mirrors.ParseLogType(e); ok && t == typ {
+				subscribed = append(subscribed, w)
+				break
+			}
+		}
+	}
+	return subscribed
+}
+
+func scheduleDelivery(conn redis.Conn, d *delivery, at time.Time) error {
+	value, err := json.Marshal(d)
+	if err != nil {
+		return err
+	}
+	_, err = conn.Do("ZADD", retryQueueKey, at.Unix(), value)
+	return err
+}
+
+func newDeliveryID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func (n *Notifier) loop() {
+	defer n.wg.Done()
+
+	ticker := time.NewTicker(10 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-n.stop:
+			return
+		case <-n.wakeup:
+		case <-ticker.C:
+			if err := n.checkOutages(); err != nil {
+				log.Errorf("Notification: unable to check outages: %s", err)
+			}
+		}
+		if err := n.processQueue(); err != nil && !n.redis.Failure() {
+			log.Errorf("Notification: unable to process the queue: %s", err)
+		}
+	}
+}
+
+// processQueue sends all the deliveries due by now
+func (n *Notifier) processQueue() error {
+	conn := n.redis.Get()
+	defer conn.Close()
+
+	values, err := redis.Strings(conn.Do("ZRANGEBYSCORE", retryQueueKey, "-inf", time.Now().Unix(), "LIMIT", 0, 100))
+	if err != nil {
+		return err
+	}
+
+	for _, value := range values {
+		select {
+		case <-n.stop:
+			return nil
+		default:
+		}
+
+		// Only one node of the cluster will be able to claim the delivery
+		removed, err := redis.Int(conn.Do("ZREM", retryQueueKey, value))
+		if err != nil {
+			return err
+		}
+		if removed == 0 {
+			continue
+		}
+
+		var d delivery
+		if err := json.Unmarshal([]byte(value), &d); err != nil {
+			log.Warningf("Notification: dropping invalid delivery: %s", err)
+			continue
+		}
+
+		webhook, ok := findWebhook(GetConfig().Notifications.Webhooks, d.URL)
+		if !ok {
+			log.Warningf("Notification: dropping delivery to %s: webhook no longer configured", d.URL)
+			continue
+		}
+
+		err = n.deliver(webhook, &d)
+		if err == nil {
+			continue
+		}
+
+		d.Attempts++
+		maxRetries := GetConfig().Notifications.MaxRetries
+		if d.Attempts > maxRetries {
+			log.Errorf("Notification: giving up delivery %s to %s after %d attempts: %s", d.ID, d.URL, d.Attempts, err)
+			continue
+		}
+
+		delay := retryDelay(time.Duration(GetConfig().Notifications.RetryInterval)*time.Second, d.Attempts)
+		log.Warningf("Notification: delivery %s to %s failed (%s), retrying in %s", d.ID, d.URL, err, delay)
+		if err := scheduleDelivery(conn, &d, time.Now().Add(delay)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func findWebhook(webhooks []Webhook, url string) (Webhook, bool) {
+	for _, w := range webhooks {
+		if w.URL == url {
+			return w, true
+		}
+	}
+	return Webhook{}, false
+}
+
+// retryDelay returns an exponential backoff based on the number of attempts
+func retryDelay(interval time.Duration, attempts int) time.Duration {
+	delay := interval
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= maxRetryDelay {
+			return maxRetryDelay
+		}
+	}
+	return delay
+}
+
+// deliver POSTs the payload of the delivery to the webhook
+func (n *Notifier) deliver(webhook Webhook, d *delivery) error {
+	req, err := http.NewRequest("POST", webhook.URL, bytes.NewReader(d.Payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "mirrorbits/"+core.VERSION)
+	req.Header.Set(EventHeader, d.Event)
+	req.Header.Set(DeliveryHeader, d.ID)
+	if webhook.Secret != "" {
+		req.Header.Set(SignatureHeader, "sha256="+Sign(webhook.Secret, d.Payload))
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(ioutil.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Sign returns the hex encoded HMAC-SHA256 of the payload
+func Sign(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,307 @@
+// Copyright (c) 2014-2019 Ludovic Fauvet
+// Licensed under the MIT license
+
+package notify
+
+import (
+	"bufio"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/opensourceways/mirrorbits/config"
+	"github.com/opensourceways/mirrorbits/mirrors"
+	. "github.com/opensourceways/mirrorbits/testing"
+	"github.com/rafaeljusto/redigomock"
+)
+
+func TestSign(t *testing.T) {
+	// Reference value computed with: echo -n 'payload' | openssl sha256 -hmac secret
+	if s := Sign("secret", []byte("payload")); s != "b82fcb791acec57859b989b430a826488ce2e479fdf92326bd0a2e8375a42ba4" {
+		t.Fatalf("Unexpected signature: %s", s)
+	}
+}
+
+func TestSubscribedWebhooks(t *testing.T) {
+	webhooks := []Webhook{
+		{URL: "http://all"},
+		{URL: "http://state", Events: []string{"StateChanged"}},
+		{URL: "http://errors", Events: []string{"error", "unknown"}},
+	}
+
+	if w := subscribedWebhooks(webhooks, mirrors.LOGTYPE_STATECHANGED); len(w) != 2 || w[0].URL != "http://all" || w[1].URL != "http://state" {
+		t.Fatalf("Unexpected webhooks for statechanged: %v", w)
+	}
+	if w := subscribedWebhooks(webhooks, mirrors.LOGTYPE_ERROR); len(w) != 2 || w[1].URL != "http://errors" {
+		t.Fatalf("Unexpected webhooks for error: %v", w)
+	}
+	if w := subscribedWebhooks(webhooks, mirrors.LOGTYPE_ADDED); len(w) != 1 {
+		t.Fatalf("Unexpected webhooks for added: %v", w)
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	if d := retryDelay(time.Minute, 1); d != time.Minute {
+		t.Fatalf("Expected 1m, got %s", d)
+	}
+	if d := retryDelay(time.Minute, 3); d != 4*time.Minute {
+		t.Fatalf("Expected 4m, got %s", d)
+	}
+	if d := retryDelay(time.Minute, 100); d != maxRetryDelay {
+		t.Fatalf("Expected %s, got %s", maxRetryDelay, d)
+	}
+}
+
+func TestNotifier_deliver(t *testing.T) {
+	var received *http.Request
+	var body []byte
+	status := http.StatusOK
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		body, _ = ioutil.ReadAll(r.Body)
+		w.WriteHeader(status)
+	}))
+	defer ts.Close()
+
+	n := &Notifier{client: ts.Client()}
+	d := &delivery{
+		ID:      "42",
+		URL:     ts.URL,
+		Event:   "statechanged",
+		Payload: json.RawMessage(`{"event":"statechanged"}`),
+	}
+
+	if err := n.deliver(Webhook{URL: ts.URL, Secret: "secret"}, d); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if received.Method != "POST" || string(body) != string(d.Payload) {
+		t.Fatalf("Unexpected request: %s %s", received.Method, body)
+	}
+	if received.Header.Get(SignatureHeader) != "sha256="+Sign("secret", d.Payload) {
+		t.Fatalf("Invalid signature: %s", received.Header.Get(SignatureHeader))
+	}
+	if received.Header.Get(EventHeader) != "statechanged" || received.Header.Get(DeliveryHeader) != "42" {
+		t.Fatalf("Missing event headers")
+	}
+
+	if err := n.deliver(Webhook{URL: ts.URL}, d); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if received.Header.Get(SignatureHeader) != "" {
+		t.Fatalf("Unsigned webhooks should not receive a signature")
+	}
+
+	status = http.StatusInternalServerError
+	if err := n.deliver(Webhook{URL: ts.URL}, d); err == nil {
+		t.Fatalf("Error expected")
+	}
+}
+
+func TestNotifier_enqueueEvent(t *testing.T) {
+	mock, conn := PrepareRedisTest()
+
+	SetConfiguration(&Configuration{
+		Notifications: Notifications{
+			Webhooks: []Webhook{
+				{URL: "http://a", Events: []string{"statechanged"}},
+				{URL: "http://b"},
+				{URL: "http://c", Events: []string{"error"}},
+			},
+		},
+	})
+
+	n := &Notifier{redis: conn, wakeup: make(chan struct{}, 1)}
+
+	cmdName := mock.Command("HGET", "MIRROR_1", "name").Expect("m1")
+	cmdQueue := mock.Command("ZADD", retryQueueKey, redigomock.NewAnyInt(), redigomock.NewAnyData()).Expect(int64(1))
+
+	if err := n.enqueueEvent(mirrors.NewLogStateChanged(1, false, "timeout")); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if mock.Stats(cmdName) != 1 {
+		t.Fatalf("Mirror name not fetched")
+	}
+	if mock.Stats(cmdQueue) != 2 {
+		t.Fatalf("Expected 2 deliveries, got %d", mock.Stats(cmdQueue))
+	}
+	select {
+	case <-n.wakeup:
+	default:
+		t.Fatalf("Delivery loop not woken up")
+	}
+}
+
+func TestNotifier_logHook(t *testing.T) {
+	// The events are queued without any access to the database
+	n := &Notifier{events: make(chan mirrors.LogAction, 1)}
+
+	first := mirrors.NewLogStateChanged(1, false, "timeout")
+	n.logHook(first)
+	// The queue is full, the event is dropped instead of blocking
+	n.logHook(mirrors.NewLogStateChanged(1, true, ""))
+
+	if len(n.events) != 1 {
+		t.Fatalf("Expected 1 queued event, got %d", len(n.events))
+	}
+	if action := <-n.events; action != first {
+		t.Fatalf("Expected the first event to be queued")
+	}
+}
+
+func TestNotifier_processQueue(t *testing.T) {
+	mock, conn := PrepareRedisTest()
+
+	var hits int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer ts.Close()
+
+	SetConfiguration(&Configuration{
+		Notifications: Notifications{
+			Webhooks:      []Webhook{{URL: ts.URL}},
+			MaxRetries:    1,
+			RetryInterval: 60,
+		},
+	})
+
+	n := &Notifier{redis: conn, client: ts.Client(), stop: make(chan struct{})}
+
+	pending, _ := json.Marshal(&delivery{ID: "1", URL: ts.URL, Payload: json.RawMessage(`{}`)})
+	exhausted, _ := json.Marshal(&delivery{ID: "2", URL: ts.URL, Payload: json.RawMessage(`{}`), Attempts: 1})
+	removed, _ := json.Marshal(&delivery{ID: "3", URL: "http://removed", Payload: json.RawMessage(`{}`)})
+
+	mock.Command("ZRANGEBYSCORE", retryQueueKey, "-inf", redigomock.NewAnyInt(), "LIMIT", 0, 100).
+		ExpectStringSlice(string(pending), string(exhausted), string(removed))
+	mock.Command("ZREM", retryQueueKey, redigomock.NewAnyData()).Expect(int64(1))
+	cmdRetry := mock.Command("ZADD", retryQueueKey, redigomock.NewAnyInt(), redigomock.NewAnyData()).Expect(int64(1))
+
+	if err := n.processQueue(); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if hits != 2 {
+		t.Fatalf("Expected 2 delivery attempts, got %d", hits)
+	}
+	if mock.Stats(cmdRetry) != 1 {
+		t.Fatalf("Expected 1 delivery to be rescheduled, got %d", mock.Stats(cmdRetry))
+	}
+}
+
+// smtpServer is a minimal SMTP server recording the received messages
+type smtpServer struct {
+	listener net.Listener
+	messages chan string
+}
+
+func newSMTPServer(t *testing.T) *smtpServer {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Unable to listen: %s", err)
+	}
+	s := &smtpServer{
+		listener: l,
+		messages: make(chan string, 1),
+	}
+	go s.serve()
+	return s
+}
+
+func (s *smtpServer) serve() {
+	for {
+		c, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(c)
+	}
+}
+
+func (s *smtpServer) handle(c net.Conn) {
+	defer c.Close()
+	r := bufio.NewReader(c)
+	reply := func(line string) {
+		c.Write([]byte(line + "\r\n"))
+	}
+
+	reply("220 localhost ESMTP")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		cmd := strings.ToUpper(strings.TrimSpace(line))
+		switch {
+		case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+			reply("250 localhost")
+		case strings.HasPrefix(cmd, "DATA"):
+			reply("354 go ahead")
+			var data strings.Builder
+			for {
+				l, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if l == ".\r\n" {
+					break
+				}
+				data.WriteString(l)
+			}
+			s.messages <- data.String()
+			reply("250 ok")
+		case strings.HasPrefix(cmd, "QUIT"):
+			reply("221 bye")
+			return
+		default:
+			reply("250 ok")
+		}
+	}
+}
+
+func (s *smtpServer) Close() {
+	s.listener.Close()
+}
+
+func TestSendMail(t *testing.T) {
+	s := newSMTPServer(t)
+	defer s.Close()
+
+	cfg := SMTPServer{
+		Address: s.listener.Addr().String(),
+		From:    "mirrorbits@example.org",
+	}
+
+	mirror := &mirrors.Mirror{
+		Name:          "m1",
+		HttpURL:       "http://m1.example.org/",
+		AdminName:     "John",
+		ExcludeReason: "Unreachable",
+	}
+
+	if err := SendMail(cfg, "admin@example.org", outageMail(mirror, time.Now().Add(-2*time.Hour))); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	select {
+	case msg := <-s.messages:
+		for _, expected := range []string{
+			"To: admin@example.org",
+			"Subject: [mirrorbits] Mirror m1 is down",
+			"Hello John,",
+			"(2h0m0s)",
+			"Reason: Unreachable",
+		} {
+			if !strings.Contains(msg, expected) {
+				t.Fatalf("Message doesn't contain %q:\n%s", expected, msg)
+			}
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("No message received")
+	}
+}
@@ -19,6 +19,7 @@ import (
 	. "github.com/opensourceways/mirrorbits/config"
 	"github.com/opensourceways/mirrorbits/core"
 	"github.com/opensourceways/mirrorbits/database"
+	"github.com/opensourceways/mirrorbits/filesystem"
 	"github.com/opensourceways/mirrorbits/mirrors"
 	"github.com/opensourceways/mirrorbits/network"
 	"github.com/opensourceways/mirrorbits/scan"
@@ -40,13 +41,20 @@ var (
 	ErrNameAlreadyTaken = errors.New("name already taken")
 )
 
+// Explainer explains the selection of the mirrors serving
+// a path to a client at the given address
+type Explainer interface {
+	Explain(ip, path string) (*mirrors.Explanation, error)
+}
+
 // CLI object handles the server side RPC of the CLI
 type CLI struct {
-	listener net.Listener
-	server   *grpc.Server
-	sig      chan<- os.Signal
-	redis    *database.Redis
-	cache    *mirrors.Cache
+	listener  net.Listener
+	server    *grpc.Server
+	sig       chan<- os.Signal
+	redis     *database.Redis
+	cache     *mirrors.Cache
+	explainer Explainer
 }
 
 func (c *CLI) Start() error {
@@ -86,6 +94,10 @@ func (c *CLI) SetCache(cache *mirrors.Cache) {
 	c.cache = cache
 }
 
+func (c *CLI) SetExplainer(e Explainer) {
+	c.explainer = e
+}
+
 func (c *CLI) Ping(context.Context, *empty.Empty) (*empty.Empty, error) {
 	return &empty.Empty{}, nil
 }
@@ -143,6 +155,26 @@ func (c *CLI) MatchMirror(ctx context.Context, in *MatchRequest) (*MatchReply, e
 	return reply, nil
 }
 
+func (c *CLI) Explain(ctx context.Context, in *ExplainRequest) (*ExplainReply, error) {
+	if c.explainer == nil {
+		return nil, status.Error(codes.Internal, "server not ready")
+	}
+	if net.ParseIP(in.IP) == nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid IP address")
+	}
+
+	explanation, err := c.explainer.Explain(in.IP, in.Path)
+	if err == filesystem.ErrOutsideRepo {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	} else if os.IsNotExist(err) {
+		return nil, status.Error(codes.NotFound, "file not found")
+	} else if err != nil {
+		return nil, errors.Wrap(err, "explain error")
+	}
+
+	return ExplanationToRPC(explanation), nil
+}
+
 func (c *CLI) ChangeStatus(ctx context.Context, in *ChangeStatusRequest) (*empty.Empty, error) {
 	if in.ID <= 0 {
 		return nil, status.Error(codes.FailedPrecondition, "invalid mirror id")
@@ -278,9 +310,14 @@ func (c *CLI) AddMirror(ctx context.Context, in *Mirror) (*AddMirrorReply, error
 		if len(mirror.Country) < 2 {
 			mirror.Country = geoRec.Country
 		}
+		if mirror.SubdivisionCode == "" {
+			mirror.SubdivisionCode = geoRec.SubdivisionCode
+		}
+		mirror.ASNGroup = geoRec.ASNGroup
 		reply.Latitude = mirror.Latitude
 		reply.Longitude = mirror.Longitude
 		reply.Continent = geoRec.ContinentCode
+		reply.Subdivision = mirror.SubdivisionCode
 		reply.ASN = fmt.Sprintf("%s (%d)", geoRec.ASName, geoRec.ASNum)
 	} else {
 		reply.Warnings = append(reply.Warnings,
@@ -317,19 +354,20 @@ func (c *CLI) UpdateMirror(ctx context.Context, in *Mirror) (*UpdateMirrorReply,
 		return nil, err
 	}
 
-	// Edit the mirrors to add the value of the country field
-	if len(mirror.Country) < 2 {
+	// Edit the mirrors to add the value of the country and subdivision fields
+	locate := len(mirror.Country) < 2
+	if locate || mirror.SubdivisionCode == "" {
 		u, err := url.Parse(mirror.HttpURL)
 		if err != nil {
 			return nil, errors.Wrap(err, "can't parse http url")
 		}
 		ip, err := network.LookupMirrorIP(u.Host)
 		geo := network.NewGeoIP()
-		if err := geo.LoadGeoIP(); err != nil {
+		if err := geo.LoadGeoIP(); err != nil && locate {
 			return nil, errors.WithStack(err)
 		}
 		geoRec := geo.GetRecord(ip)
-		if geoRec.IsValid() {
+		if geoRec.IsValid() && locate {
 			if len(mirror.Country) < 2 {
 				mirror.Country = geoRec.Country
 			}
@@ -343,7 +381,13 @@ func (c *CLI) UpdateMirror(ctx context.Context, in *Mirror) (*UpdateMirrorReply,
 			mirror.CountryCodes = geoRec.CountryCode
 			mirror.Asnum = geoRec.ASNum
 		}
+		if geoRec.IsValid() && mirror.SubdivisionCode == "" {
+			mirror.SubdivisionCode = geoRec.SubdivisionCode
+		}
 	}
+	// Tag the mirror with the ASN group of its AS number
+	mirror.ASNGroup = network.ASNGroupOf(mirror.Asnum)
+
 	diff := createDiff(&original, mirror)
 	return &UpdateMirrorReply{
 		Diff: diff,
@@ -402,9 +446,23 @@ func (c *CLI) setMirror(mirror *mirrors.Mirror) error {
 	//mirror.Country = utils.SanitizeLocationCodes(mirror.Country)
 	mirror.ExcludedCountryCodes = utils.SanitizeLocationCodes(mirror.ExcludedCountryCodes)
 
+	// Apply the region aliases
+	mirror.ApplyRegionAliases()
+
+	// Validate the share caps
+	if err := mirror.ShareCaps.Normalize(); err != nil {
+		return err
+	}
+
+	// Reformat subdivision code
+	mirror.SubdivisionCode = strings.ToUpper(strings.TrimSpace(mirror.SubdivisionCode))
+
 	// Reformat continent code
 	mirror.ContinentCode = utils.SanitizeLocationCodes(mirror.ContinentCode)
 
+	// Reformat the allowed redirect domains
+	mirror.AllowedRedirectDomains = strings.ToLower(strings.Join(strings.Fields(strings.Replace(mirror.AllowedRedirectDomains, ",", " ", -1)), " "))
+
 	// Normalize URLs
 	if mirror.HttpURL != "" {
 		mirror.HttpURL = utils.NormalizeURL(mirror.HttpURL)
@@ -433,6 +491,8 @@ func (c *CLI) setMirror(mirror *mirrors.Mirror) error {
 		"continentOnly", mirror.ContinentOnly,
 		"countryOnly", mirror.CountryOnly,
 		"asOnly", mirror.ASOnly,
+		"private", mirror.Private,
+		"shareCaps", mirror.ShareCaps,
 		"score", mirror.Score,
 		"latitude", mirror.Latitude,
 		"longitude", mirror.Longitude,
@@ -440,10 +500,13 @@ func (c *CLI) setMirror(mirror *mirrors.Mirror) error {
 		"continentCode", mirror.ContinentCode,
 		"countryCodes", mirror.CountryCodes,
 		"country", mirror.Country,
+		"subdivisionCode", mirror.SubdivisionCode,
 		"excludedCountryCodes", mirror.ExcludedCountryCodes,
 		"asnum", mirror.Asnum,
+		"asnGroup", mirror.ASNGroup,
 		"comment", mirror.Comment,
 		"allowredirects", mirror.AllowRedirects,
+		"allowedRedirectDomains", mirror.AllowedRedirectDomains,
 		"enabled", mirror.Enabled)
 
 	// The name of the mirror has been changed.
@@ -762,3 +825,130 @@ func (c *CLI) GetMirrorLogs(ctx context.Context, in *GetMirrorLogsRequest) (*Get
 
 	return &GetMirrorLogsReply{Line: lines}, nil
 }
+
+func (c *CLI) AddMaintenance(ctx context.Context, in *AddMaintenanceRequest) (*MaintenanceWindow, error) {
+	if in.MirrorID <= 0 {
+		return nil, status.Error(codes.FailedPrecondition, "invalid mirror id")
+	}
+	if in.Window == nil {
+		return nil, status.Error(codes.InvalidArgument, "missing maintenance window")
+	}
+
+	start, err := ptypes.Timestamp(in.Window.Start)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid start date")
+	}
+	end, err := ptypes.Timestamp(in.Window.End)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid end date")
+	}
+
+	window, err := mirrors.AddMaintenanceWindow(c.redis, int(in.MirrorID), start, end, in.Window.Reason)
+	if err == mirrors.ErrInvalidMaintenanceWindow {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	} else if err == mirrors.ErrMirrorNotFound {
+		return nil, status.Error(codes.NotFound, err.Error())
+	} else if err == mirrors.ErrConcurrentUpdate {
+		return nil, status.Error(codes.Aborted, err.Error())
+	} else if err != nil {
+		return nil, errors.Wrap(err, "add maintenance error")
+	}
+
+	return MaintenanceWindowToRPC(window)
+}
+
+func (c *CLI) RemoveMaintenance(ctx context.Context, in *RemoveMaintenanceRequest) (*empty.Empty, error) {
+	if in.MirrorID <= 0 {
+		return nil, status.Error(codes.FailedPrecondition, "invalid mirror id")
+	}
+
+	err := mirrors.RemoveMaintenanceWindow(c.redis, int(in.MirrorID), int(in.WindowID))
+	if err == mirrors.ErrMaintenanceWindowNotFound || err == mirrors.ErrMirrorNotFound {
+		return nil, status.Error(codes.NotFound, err.Error())
+	} else if err == mirrors.ErrConcurrentUpdate {
+		return nil, status.Error(codes.Aborted, err.Error())
+	} else if err != nil {
+		return nil, errors.Wrap(err, "remove maintenance error")
+	}
+
+	return &empty.Empty{}, nil
+}
+
+func (c *CLI) ListMaintenance(ctx context.Context, in *MirrorIDRequest) (*ListMaintenanceReply, error) {
+	if in.ID <= 0 {
+		return nil, status.Error(codes.FailedPrecondition, "invalid mirror id")
+	}
+
+	windows, err := mirrors.GetMaintenanceWindows(c.redis, int(in.ID))
+	if err != nil {
+		return nil, errors.Wrap(err, "list maintenance error")
+	}
+
+	reply := &ListMaintenanceReply{}
+	for _, w := range windows {
+		rw, err := MaintenanceWindowToRPC(w)
+		if err != nil {
+			return nil, err
+		}
+		reply.Windows = append(reply.Windows, rw)
+	}
+	return reply, nil
+}
+
+func (c *CLI) AddNetworkRule(ctx context.Context, in *AddNetworkRuleRequest) (*NetworkRule, error) {
+	if in.MirrorID <= 0 {
+		return nil, status.Error(codes.FailedPrecondition, "invalid mirror id")
+	}
+	if in.Rule == nil {
+		return nil, status.Error(codes.InvalidArgument, "missing network rule")
+	}
+
+	rule, err := mirrors.AddNetworkRule(c.redis, int(in.MirrorID), in.Rule.Network, in.Rule.Exclusive)
+	if err == mirrors.ErrInvalidNetwork {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	} else if err == mirrors.ErrMirrorNotFound {
+		return nil, status.Error(codes.NotFound, err.Error())
+	} else if err == mirrors.ErrConcurrentUpdate {
+		return nil, status.Error(codes.Aborted, err.Error())
+	} else if err != nil {
+		return nil, errors.Wrap(err, "add network rule error")
+	}
+
+	return NetworkRuleToRPC(rule), nil
+}
+
+func (c *CLI) RemoveNetworkRule(ctx context.Context, in *RemoveNetworkRuleRequest) (*empty.Empty, error) {
+	if in.MirrorID <= 0 {
+		return nil, status.Error(codes.FailedPrecondition, "invalid mirror id")
+	}
+
+	err := mirrors.RemoveNetworkRule(c.redis, int(in.MirrorID), in.Network)
+	if err == mirrors.ErrInvalidNetwork {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	} else if err == mirrors.ErrNetworkRuleNotFound || err == mirrors.ErrMirrorNotFound {
+		return nil, status.Error(codes.NotFound, err.Error())
+	} else if err == mirrors.ErrConcurrentUpdate {
+		return nil, status.Error(codes.Aborted, err.Error())
+	} else if err != nil {
+		return nil, errors.Wrap(err, "remove network rule error")
+	}
+
+	return &empty.Empty{}, nil
+}
+
+func (c *CLI) ListNetworkRules(ctx context.Context, in *MirrorIDRequest) (*ListNetworkRulesReply, error) {
+	if in.ID <= 0 {
+		return nil, status.Error(codes.FailedPrecondition, "invalid mirror id")
+	}
+
+	rules, err := mirrors.GetNetworkRules(c.redis, int(in.ID))
+	if err != nil {
+		return nil, errors.Wrap(err, "list network rules error")
+	}
+
+	reply := &ListNetworkRulesReply{}
+	for _, r := range rules {
+		reply.Rules = append(reply.Rules, NetworkRuleToRPC(r))
+	}
+	return reply, nil
+}
@@ -4,8 +4,8 @@
 package rpc
 
 import (
-	"github.com/opensourceways/mirrorbits/mirrors"
 	"github.com/golang/protobuf/ptypes"
+	"github.com/opensourceways/mirrorbits/mirrors"
 )
 
 func MirrorToRPC(m *mirrors.Mirror) (*Mirror, error) {
@@ -26,38 +26,45 @@ func MirrorToRPC(m *mirrors.Mirror) (*Mirror, error) {
 		return nil, err
 	}
 	return &Mirror{
-		ID:                   int32(m.ID),
-		Name:                 m.Name,
-		HttpURL:              m.HttpURL,
-		RsyncURL:             m.RsyncURL,
-		FtpURL:               m.FtpURL,
-		SponsorName:          m.SponsorName,
-		SponsorURL:           m.SponsorURL,
-		SponsorLogoURL:       m.SponsorLogoURL,
-		AdminName:            m.AdminName,
-		AdminEmail:           m.AdminEmail,
-		CustomData:           m.CustomData,
-		ContinentOnly:        m.ContinentOnly,
-		CountryOnly:          m.CountryOnly,
-		ASOnly:               m.ASOnly,
-		Score:                int32(m.Score),
-		Latitude:             m.Latitude,
-		Longitude:            m.Longitude,
-		ContinentCode:        m.ContinentCode,
-		CountryCodes:         m.CountryCodes,
-		ExcludedCountryCodes: m.ExcludedCountryCodes,
-		Asnum:                uint32(m.Asnum),
-		Comment:              m.Comment,
-		Enabled:              m.Enabled,
-		Up:                   m.Up,
-		ExcludeReason:        m.ExcludeReason,
-		StateSince:           stateSince,
-		AllowRedirects:       int32(m.AllowRedirects),
-		LastSync:             lastSync,
-		LastSuccessfulSync:   lastSuccessfulSync,
-		LastModTime:          lastModTime,
-		Country:              m.Country,
-		NetworkBandwidth:     m.NetworkBandwidth,
+		ID:                     int32(m.ID),
+		Name:                   m.Name,
+		HttpURL:                m.HttpURL,
+		RsyncURL:               m.RsyncURL,
+		FtpURL:                 m.FtpURL,
+		SponsorName:            m.SponsorName,
+		SponsorURL:             m.SponsorURL,
+		SponsorLogoURL:         m.SponsorLogoURL,
+		AdminName:              m.AdminName,
+		AdminEmail:             m.AdminEmail,
+		CustomData:             m.CustomData,
+		ContinentOnly:          m.ContinentOnly,
+		CountryOnly:            m.CountryOnly,
+		ASOnly:                 m.ASOnly,
+		Score:                  int32(m.Score),
+		Latitude:               m.Latitude,
+		Longitude:              m.Longitude,
+		ContinentCode:          m.ContinentCode,
+		CountryCodes:           m.CountryCodes,
+		ExcludedCountryCodes:   m.ExcludedCountryCodes,
+		Asnum:                  uint32(m.Asnum),
+		Comment:                m.Comment,
+		Enabled:                m.Enabled,
+		Up:                     m.Up,
+		ExcludeReason:          m.ExcludeReason,
+		StateSince:             stateSince,
+		AllowRedirects:         int32(m.AllowRedirects),
+		LastSync:               lastSync,
+		LastSuccessfulSync:     lastSuccessfulSync,
+		LastModTime:            lastModTime,
+		Country:                m.Country,
+		NetworkBandwidth:       m.NetworkBandwidth,
+		AllowedRedirectDomains: m.AllowedRedirectDomains,
+		ResolvedURL:            m.ResolvedURL,
+		InMaintenance:          m.InMaintenance(),
+		Private:                m.Private,
+		ShareCaps:              ShareCapsToRPC(m.ShareCaps),
+		SubdivisionCode:        m.SubdivisionCode,
+		ASNGroup:               m.ASNGroup,
 	}, nil
 }
 
@@ -79,37 +86,131 @@ func MirrorFromRPC(m *Mirror) (*mirrors.Mirror, error) {
 		return nil, err
 	}
 	return &mirrors.Mirror{
-		ID:                   int(m.ID),
-		Name:                 m.Name,
-		HttpURL:              m.HttpURL,
-		RsyncURL:             m.RsyncURL,
-		FtpURL:               m.FtpURL,
-		SponsorName:          m.SponsorName,
-		SponsorURL:           m.SponsorURL,
-		SponsorLogoURL:       m.SponsorLogoURL,
-		AdminName:            m.AdminName,
-		AdminEmail:           m.AdminEmail,
-		CustomData:           m.CustomData,
-		ContinentOnly:        m.ContinentOnly,
-		CountryOnly:          m.CountryOnly,
-		ASOnly:               m.ASOnly,
-		Score:                int(m.Score),
-		Latitude:             m.Latitude,
-		Longitude:            m.Longitude,
-		ContinentCode:        m.ContinentCode,
-		CountryCodes:         m.CountryCodes,
-		ExcludedCountryCodes: m.ExcludedCountryCodes,
-		Asnum:                uint(m.Asnum),
-		Comment:              m.Comment,
-		Enabled:              m.Enabled,
-		Up:                   m.Up,
-		ExcludeReason:        m.ExcludeReason,
-		StateSince:           mirrors.Time{}.FromTime(stateSince),
-		AllowRedirects:       mirrors.Redirects(m.AllowRedirects),
-		LastSync:             mirrors.Time{}.FromTime(lastSync),
-		LastSuccessfulSync:   mirrors.Time{}.FromTime(lastSuccessfulSync),
-		LastModTime:          mirrors.Time{}.FromTime(lastModTime),
-		Country:              m.Country,
-		NetworkBandwidth:     m.NetworkBandwidth,
+		ID:                     int(m.ID),
+		Name:                   m.Name,
+		HttpURL:                m.HttpURL,
+		RsyncURL:               m.RsyncURL,
+		FtpURL:                 m.FtpURL,
+		SponsorName:            m.SponsorName,
+		SponsorURL:             m.SponsorURL,
+		SponsorLogoURL:         m.SponsorLogoURL,
+		AdminName:              m.AdminName,
+		AdminEmail:             m.AdminEmail,
+		CustomData:             m.CustomData,
+		ContinentOnly:          m.ContinentOnly,
+		CountryOnly:            m.CountryOnly,
+		ASOnly:                 m.ASOnly,
+		Score:                  int(m.Score),
+		Latitude:               m.Latitude,
+		Longitude:              m.Longitude,
+		ContinentCode:          m.ContinentCode,
+		CountryCodes:           m.CountryCodes,
+		ExcludedCountryCodes:   m.ExcludedCountryCodes,
+		Asnum:                  uint(m.Asnum),
+		Comment:                m.Comment,
+		Enabled:                m.Enabled,
+		Up:                     m.Up,
+		ExcludeReason:          m.ExcludeReason,
+		StateSince:             mirrors.Time{}.FromTime(stateSince),
+		AllowRedirects:         mirrors.Redirects(m.AllowRedirects),
+		LastSync:               mirrors.Time{}.FromTime(lastSync),
+		LastSuccessfulSync:     mirrors.Time{}.FromTime(lastSuccessfulSync),
+		LastModTime:            mirrors.Time{}.FromTime(lastModTime),
+		Country:                m.Country,
+		NetworkBandwidth:       m.NetworkBandwidth,
+		AllowedRedirectDomains: m.AllowedRedirectDomains,
+		ResolvedURL:            m.ResolvedURL,
+		Private:                m.Private,
+		ShareCaps:              ShareCapsFromRPC(m.ShareCaps),
+		SubdivisionCode:        m.SubdivisionCode,
+		ASNGroup:               m.ASNGroup,
+	}, nil
+}
+
+func ShareCapsToRPC(caps mirrors.ShareCaps) []*ShareCap {
+	var ret []*ShareCap
+	for _, c := range caps {
+		ret = append(ret, &ShareCap{
+			Share:     c.Share,
+			Country:   c.Country,
+			Continent: c.Continent,
+		})
+	}
+	return ret
+}
+
+func ShareCapsFromRPC(caps []*ShareCap) mirrors.ShareCaps {
+	var ret mirrors.ShareCaps
+	for _, c := range caps {
+		ret = append(ret, mirrors.ShareCap{
+			Share:     c.Share,
+			Country:   c.Country,
+			Continent: c.Continent,
+		})
+	}
+	return ret
+}
+
+func MaintenanceWindowToRPC(w mirrors.MaintenanceWindow) (*MaintenanceWindow, error) {
+	start, err := ptypes.TimestampProto(w.Start)
+	if err != nil {
+		return nil, err
+	}
+	end, err := ptypes.TimestampProto(w.End)
+	if err != nil {
+		return nil, err
+	}
+	return &MaintenanceWindow{
+		ID:     int32(w.ID),
+		Start:  start,
+		End:    end,
+		Reason: w.Reason,
 	}, nil
 }
+
+func NetworkRuleToRPC(r mirrors.NetworkRule) *NetworkRule {
+	return &NetworkRule{
+		Network:   r.Network,
+		Exclusive: r.Exclusive,
+	}
+}
+
+func ExplanationToRPC(e *mirrors.Explanation) *ExplainReply {
+	reply := &ExplainReply{
+		IP: e.IP,
+		ClientInfo: &ClientInfo{
+			CountryCode:     e.ClientInfo.CountryCode,
+			ContinentCode:   e.ClientInfo.ContinentCode,
+			City:            e.ClientInfo.City,
+			Country:         e.ClientInfo.Country,
+			SubdivisionCode: e.ClientInfo.SubdivisionCode,
+			Latitude:        e.ClientInfo.Latitude,
+			Longitude:       e.ClientInfo.Longitude,
+			ASName:          e.ClientInfo.ASName,
+			ASNum:           uint32(e.ClientInfo.ASNum),
+			ASNGroup:        e.ClientInfo.ASNGroup,
+		},
+		Path:     e.FileInfo.Path,
+		Size:     e.FileInfo.Size,
+		Fallback: e.Fallback,
+	}
+	for _, m := range e.Mirrors {
+		reply.Mirrors = append(reply.Mirrors, &ExplainedMirror{
+			ID:            int32(m.ID),
+			Name:          m.Name,
+			Rank:          int32(m.Rank),
+			Distance:      m.Distance,
+			CountryScore:  int32(m.CountryScore),
+			Score:         int32(m.Score),
+			DistanceScore: int32(m.DistanceScore),
+			Weight:        m.Weight,
+			Utilization:   m.Utilization,
+			NetworkMatch:  m.NetworkMatch,
+			ShareCapped:   m.ShareCapped,
+			ASNGroupMatch: m.ASNGroupMatch,
+			FileMatch:     m.FileMatch,
+			ExcludeReason: m.ExcludeReason,
+		})
+	}
+	return reply
+}
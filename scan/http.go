@@ -3,11 +3,13 @@
 package scan
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"github.com/go-resty/resty/v2"
 	"github.com/opensourceways/mirrorbits/core"
 	"github.com/opensourceways/mirrorbits/filesystem"
+	"github.com/opensourceways/mirrorbits/mirrors"
 	"github.com/opensourceways/mirrorbits/utils"
 	"net/http"
 	"net/url"
@@ -16,11 +18,18 @@ import (
 	"time"
 )
 
-var mirrorCheckClient = resty.New().RemoveProxy().SetHeader(userAgentName, userAgent)
+var mirrorCheckClient = resty.New().RemoveProxy().
+	SetHeader(userAgentName, userAgent).
+	SetRedirectPolicy(resty.RedirectPolicyFunc(mirrors.CheckRedirect))
+
+// fileDelay is the delay between the requests of the files to a
+// mirror, doubled whenever the mirror answers 429
+var fileDelay = time.Second
 
 // HttpScanner is the implementation of an http scanner
 type HttpScanner struct {
 	scan *scan
+	ctx  context.Context // carries the redirect policy of the mirror
 }
 
 // Scan starts an http head scan of the given mirror
@@ -42,56 +51,76 @@ func (r *HttpScanner) Scan(httpUrl, identifier string, repoVersion []*filesystem
 	if err != nil {
 		return 0, filePath, err
 	}
-	client := mirrorCheckClient
-	head, err := client.R().Head(uri.String())
-	if err != nil {
-		return 0, filePath, err
+	ctx := r.ctx
+	if ctx == nil {
+		ctx = context.Background()
 	}
+	client := mirrorCheckClient
+	head, err := client.R().SetContext(ctx).Head(uri.String())
 	if err != nil {
 		return 0, filePath, err
 	}
 	if head.StatusCode() != http.StatusOK {
 		return 0, filePath, errors.New(identifier + " mirror http url: " + head.Status() + " " + httpUrl + " request failed")
 	}
+	if head.RawResponse != nil && head.RawResponse.Request != nil {
+		if resolved := mirrors.ResolvedBaseURL(head.RawResponse.Request.URL, ""); resolved != "" {
+			mirrors.SetMirrorResolvedURL(r.scan.redis, r.scan.mirrorid, resolved)
+		}
+	}
 
-	fd := filesystem.FileData{}
+	// Every file found is indexed on its own path, the first missing
+	// or differing file being reported once the others are checked
+	var failedPath string
+	var failure error
 	counter := 0
 	for i, fl := range fileList {
+		if utils.IsStopped(stop) {
+			return 0, filePath, ErrScanAborted
+		}
 		fileUrl := fl.Dir + filesystem.Sep + fl.Name
-		time.Sleep(time.Duration(1<<counter) * time.Second)
+		time.Sleep(fileDelay << counter)
 	retry:
 		headFileUrl := utils.ConcatURL(uri.String(), fileUrl)
-		head1, err1 := client.R().Head(headFileUrl)
+		head1, err1 := client.R().SetContext(ctx).Head(headFileUrl)
 		if err1 != nil {
-			return 0, filePath, err
+			return 0, fileUrl, err1
 		}
 		if head1.StatusCode() == http.StatusTooManyRequests {
 			counter++
-			time.Sleep(time.Duration(1<<counter) * time.Second)
+			time.Sleep(fileDelay << counter)
 			if counter <= 4 {
 				goto retry
 			}
 		}
 		if head1.StatusCode() != http.StatusOK {
-			return 0, filePath, fmt.Errorf("file no.%d, http url: %s request failed, response status: %s", i, headFileUrl, head1.Status())
+			if failure == nil {
+				failedPath = fileUrl
+				failure = fmt.Errorf("file no.%d, http url: %s request failed, response status: %s", i, headFileUrl, head1.Status())
+			}
+			continue
 		}
 
 		sizeStr := head1.Header().Get("Content-Length")
 		size, _ := strconv.ParseInt(sizeStr, 10, 64)
-		fd.Path = filePath
-		fd.Size = size
 
 		sourceFile := filesystem.GetRepoFileData(fileUrl)
 		if size == 0 || sourceFile.Size != size {
-			return 0, filePath, fmt.Errorf("file no.%d, http url: %s, size mismatch: %d[dest] != %d[src]", i, headFileUrl, size, sourceFile.Size)
+			if failure == nil {
+				failedPath = fileUrl
+				failure = fmt.Errorf("file no.%d, http url: %s, size mismatch: %d[dest] != %d[src]", i, headFileUrl, size, sourceFile.Size)
+			}
+			continue
 		}
 
 		modTimeStr := head1.Header().Get("Last-Modified")
 		modTime, _ := time.Parse(time.RFC1123, modTimeStr)
-		fd.ModTime = modTime
-		r.scan.ScannerAddFile(fd)
-
+		r.scan.ScannerAddFile(filesystem.FileData{
+			Path:    fileUrl,
+			Size:    size,
+			ModTime: modTime,
+		})
 	}
 
-	return 0, "", nil
+	return 0, failedPath, failure
 }
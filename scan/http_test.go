@@ -0,0 +1,249 @@
+// Copyright (c) 2014-2019 Ludovic Fauvet
+// Licensed under the MIT license
+
+package scan
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	. "github.com/opensourceways/mirrorbits/config"
+	"github.com/opensourceways/mirrorbits/core"
+	"github.com/opensourceways/mirrorbits/database"
+	"github.com/opensourceways/mirrorbits/filesystem"
+	"github.com/opensourceways/mirrorbits/mirrors"
+	"github.com/opensourceways/mirrorbits/network"
+	. "github.com/opensourceways/mirrorbits/testing"
+)
+
+const testVersion = "openEuler-24.03-LTS"
+
+var testFiles = map[string]int64{
+	testVersion + "/ISO/x86_64/a.iso":  1024,
+	testVersion + "/ISO/x86_64/b.iso":  2048,
+	testVersion + "/ISO/aarch64/c.iso": 4096,
+}
+
+// prepareRepository builds the file tree of the local repository
+func prepareRepository(t *testing.T, files map[string]int64) {
+	filter := DirFilter{SecondDir: []string{"ISO"}, ThirdDir: []string{"x86_64", "aarch64"}}
+	SetConfiguration(&Configuration{Repository: t.TempDir(), RepositoryFilter: filter})
+	filesystem.InitPathFilter(filter)
+
+	modTime := time.Now().Add(-time.Hour).UTC().Format("2006/01/02 15:04:05")
+	for path, size := range files {
+		if filesystem.IsRepoMetadata(path) {
+			filesystem.AddRepoMetadata(filesystem.NewFileData(path, []byte(" "+strconv.FormatInt(size, 10)), []byte(modTime), GetConfig()))
+			continue
+		}
+		filesystem.BuildFileTree(path, []byte(" "+strconv.FormatInt(size, 10)), []byte(modTime), GetConfig())
+	}
+	filesystem.UpdateFileTree(filter)
+}
+
+// newMirror starts a mirror serving the given files and adds it to the database
+func newMirror(t *testing.T, m *miniredis.Miniredis, id int, files map[string]int64) *httptest.Server {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimPrefix(r.URL.Path, "/")
+		if path == "" {
+			return
+		}
+		size, ok := files[path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Length", strconv.FormatInt(size, 10))
+		w.Header().Set("Last-Modified", time.Now().UTC().Format(http.TimeFormat))
+	}))
+	t.Cleanup(server.Close)
+
+	transport := mirrorCheckClient.GetClient().Transport
+	mirrorCheckClient.SetTransport(server.Client().Transport)
+	t.Cleanup(func() { mirrorCheckClient.SetTransport(transport) })
+
+	name := "m" + strconv.Itoa(id)
+	m.HSet("MIRRORS", strconv.Itoa(id), name)
+	m.HSet("MIRROR_"+strconv.Itoa(id),
+		"ID", strconv.Itoa(id),
+		"name", name,
+		"http", server.URL+"/",
+		"enabled", "1",
+		"up", "1")
+	return server
+}
+
+// scanMirrors scans the mirrors of the database with the HTTP scanner
+func scanMirrors(t *testing.T, r *database.Redis, servers map[int]*httptest.Server) {
+	delay := fileDelay
+	fileDelay = 0
+	defer func() { fileDelay = delay }()
+
+	for id, server := range servers {
+		if _, err := Scan(core.HTTP, r, nil, server.URL+"/", id, nil); err != nil {
+			t.Fatalf("Scan of mirror %d failed: %s", id, err)
+		}
+	}
+}
+
+func TestScanToSelection(t *testing.T) {
+	prepareRepository(t, testFiles)
+
+	m, r := PrepareMiniredisTest(t)
+	r.ConnectPubsub()
+
+	partial := map[string]int64{}
+	for path, size := range testFiles {
+		if !strings.HasSuffix(path, "b.iso") {
+			partial[path] = size
+		}
+	}
+	m1 := newMirror(t, m, 1, testFiles)
+	scanMirrors(t, r, map[int]*httptest.Server{1: m1})
+	m2 := newMirror(t, m, 2, partial)
+	scanMirrors(t, r, map[int]*httptest.Server{2: m2})
+
+	// Every file is indexed on its own path along with its own size
+	for path, size := range testFiles {
+		if got := m.HGet("FILEINFO_1_"+path, "size"); got != strconv.FormatInt(size, 10) {
+			t.Fatalf("Expected the size %d for %s, got %q", size, path, got)
+		}
+	}
+
+	cache := mirrors.NewCache(r)
+	clientInfo := network.GeoIPRecord{}
+
+	for path := range testFiles {
+		files, dir, isDir := filesystem.GetIndexedFiles("/" + path)
+		if len(files) != 1 || files[0] != path || dir != "" || isDir {
+			t.Fatalf("Expected %s to be indexed, got %v in %q", path, files, dir)
+		}
+		mlist, err := cache.GetMirrors(path, clientInfo)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		expected := 2
+		if path == testVersion+"/ISO/x86_64/b.iso" {
+			expected = 1
+		}
+		if len(mlist) != expected {
+			t.Fatalf("Expected %d mirrors for %s, got %d", expected, path, len(mlist))
+		}
+		for _, mirror := range mlist {
+			if mirror.FileInfo == nil || mirror.FileInfo.Size != testFiles[path] {
+				t.Fatalf("Expected the size of %s on %s, got %+v", path, mirror.Name, mirror.FileInfo)
+			}
+		}
+	}
+
+	// Directories are served by the mirrors carrying all their indexed files
+	for dir, expected := range map[string]int{
+		testVersion:                  1,
+		testVersion + "/ISO/x86_64":  1,
+		testVersion + "/ISO/aarch64": 2,
+	} {
+		files, scope, isDir := filesystem.GetIndexedFiles("/" + dir)
+		if !isDir || scope != dir || len(files) == 0 {
+			t.Fatalf("Expected the indexed files of %s, got %v in %q", dir, files, scope)
+		}
+		mlist, err := cache.GetDirMirrors(scope, files, clientInfo)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if len(mlist) != expected {
+			t.Fatalf("Expected %d mirrors for %s, got %d", expected, dir, len(mlist))
+		}
+	}
+}
+
+func TestScanToCoverage(t *testing.T) {
+	prepareRepository(t, testFiles)
+
+	m, r := PrepareMiniredisTest(t)
+	r.ConnectPubsub()
+
+	partial := map[string]int64{}
+	aarch64 := map[string]int64{}
+	for path, size := range testFiles {
+		if !strings.HasSuffix(path, "b.iso") {
+			partial[path] = size
+		}
+		if strings.Contains(path, "/aarch64/") {
+			aarch64[path] = size
+		}
+	}
+	scanMirrors(t, r, map[int]*httptest.Server{1: newMirror(t, m, 1, testFiles)})
+	scanMirrors(t, r, map[int]*httptest.Server{2: newMirror(t, m, 2, partial)})
+	scanMirrors(t, r, map[int]*httptest.Server{3: newMirror(t, m, 3, aarch64)})
+
+	cache := mirrors.NewCache(r)
+	targets := filesystem.GetCoverageTargets()
+	if len(targets) != 2 || targets[0].Arch != "aarch64" || targets[1].Arch != "x86_64" {
+		t.Fatalf("Expected the aarch64 and x86_64 targets, got %+v", targets)
+	}
+
+	now := time.Now()
+	matrix, err := cache.Coverage(targets, []int{1, 2, 3}, time.Hour, now)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if len(matrix.Rows) != 3 {
+		t.Fatalf("Expected 3 rows, got %d", len(matrix.Rows))
+	}
+	for i, expected := range [][]string{
+		{mirrors.CoveragePresent, mirrors.CoveragePresent},
+		{mirrors.CoveragePresent, mirrors.CoverageStale},
+		{mirrors.CoveragePresent, mirrors.CoverageMissing},
+	} {
+		for j, cell := range matrix.Rows[i].Cells {
+			if cell.State != expected[j] {
+				t.Fatalf("Expected %s for %s on %s, got %s", expected[j], targets[j].Arch, matrix.Rows[i].Name, cell.State)
+			}
+		}
+	}
+
+	// The index is outdated once the scans are too old
+	matrix, err = cache.Coverage(targets, []int{1, 2, 3}, time.Hour, now.Add(2*time.Hour))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if state := matrix.Rows[0].Cells[0].State; state != mirrors.CoverageStale {
+		t.Fatalf("Expected %s, got %s", mirrors.CoverageStale, state)
+	}
+}
+
+func TestScanRepoMetadata(t *testing.T) {
+	metadata := testVersion + "/everything/x86_64/" + filesystem.RepoMetadataFile
+	files := map[string]int64{metadata: 512}
+	outdated := map[string]int64{metadata: 256}
+	for path, size := range testFiles {
+		files[path] = size
+		outdated[path] = size
+	}
+	prepareRepository(t, files)
+
+	m, r := PrepareMiniredisTest(t)
+	r.ConnectPubsub()
+
+	scanMirrors(t, r, map[int]*httptest.Server{1: newMirror(t, m, 1, files)})
+	scanMirrors(t, r, map[int]*httptest.Server{2: newMirror(t, m, 2, outdated)})
+
+	if ids, _ := m.Members("FILEMIRRORS_" + metadata); len(ids) != 1 || ids[0] != "1" {
+		t.Fatalf("Expected the metadata to be indexed on the up to date mirror only, got %v", ids)
+	}
+
+	// The metalinks are built on the mirrors carrying the metadata
+	cache := mirrors.NewCache(r)
+	mlist, err := cache.GetMirrors(metadata, network.GeoIPRecord{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if len(mlist) != 1 || mlist[0].ID != 1 {
+		t.Fatalf("Expected the mirror 1, got %d mirrors", len(mlist))
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright (c) 2014-2019 Ludovic Fauvet
+// Licensed under the MIT license
+
+package scan
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/opensourceways/mirrorbits/metrics"
+)
+
+var (
+	scansTotal = metrics.NewCounter("mirrorbits_scans_total",
+		"Scans of the mirrors by mirror and outcome.",
+		"mirror", "outcome")
+	scanDuration = metrics.NewGauge("mirrorbits_scan_duration_seconds",
+		"Duration of the last scan of the mirrors by mirror.",
+		"mirror")
+	sourceScansTotal = metrics.NewCounter("mirrorbits_source_scans_total",
+		"Scans of the local repository by outcome.",
+		"outcome")
+	sourceScanDuration = metrics.NewGauge("mirrorbits_source_scan_duration_seconds",
+		"Duration of the last scan of the local repository.")
+	sourceFiles = metrics.NewGauge("mirrorbits_source_files",
+		"Files indexed by the last successful scan of the local repository.")
+
+	// lastSourceScan is the unix time of the last successful scan of the local repository
+	lastSourceScan int64
+)
+
+func init() {
+	err := metrics.NewGaugeFunc("mirrorbits_source_scan_age_seconds",
+		"Time elapsed since the last successful scan of the local repository.", nil,
+		func(emit metrics.Emit) {
+			if last := LastSourceScan(); !last.IsZero() {
+				emit(time.Since(last).Seconds())
+			}
+		})
+	if err != nil {
+		panic(err)
+	}
+}
+
+// outcome returns the label of the outcome of a scan
+func outcome(err error) string {
+	switch err {
+	case nil:
+		return "success"
+	case ErrScanAborted:
+		return "aborted"
+	case ErrScanInProgress:
+		return "in_progress"
+	}
+	return "failure"
+}
+
+// observeScan records a scan of a mirror
+func observeScan(name string, start time.Time, err error) {
+	scansTotal.WithLabelValues(name, outcome(err)).Inc()
+	if err == nil {
+		scanDuration.WithLabelValues(name).Set(time.Since(start).Seconds())
+	}
+}
+
+// observeSourceScan records a scan of the local repository
+func observeSourceScan(start time.Time, count int, err error) {
+	sourceScansTotal.WithLabelValues(outcome(err)).Inc()
+	if err == nil {
+		sourceScanDuration.WithLabelValues().Set(time.Since(start).Seconds())
+		sourceFiles.WithLabelValues().Set(float64(count))
+		atomic.StoreInt64(&lastSourceScan, time.Now().Unix())
+	}
+}
+
+// LastSourceScan returns the time of the last successful scan of the
+// local repository by this process, zero if none
+func LastSourceScan() time.Time {
+	if last := atomic.LoadInt64(&lastSourceScan); last > 0 {
+		return time.Unix(last, 0)
+	}
+	return time.Time{}
+}
@@ -5,6 +5,7 @@ package scan
 
 import (
 	"bufio"
+	"context"
 	"errors"
 	"fmt"
 	"os"
@@ -63,7 +64,7 @@ func IsScanning(conn redis.Conn, id int) (bool, error) {
 }
 
 // Scan starts a scan of the given mirror
-func Scan(typ core.ScannerType, r *database.Redis, c *mirrors.Cache, url string, id int, stop <-chan struct{}) (*ScanResult, error) {
+func Scan(typ core.ScannerType, r *database.Redis, c *mirrors.Cache, url string, id int, stop <-chan struct{}) (res *ScanResult, err error) {
 	// Connect to the database
 	conn := r.Get()
 	defer conn.Close()
@@ -85,6 +86,22 @@ func Scan(typ core.ScannerType, r *database.Redis, c *mirrors.Cache, url string,
 		return nil, err
 	}
 
+	start := time.Now()
+	defer func() {
+		observeScan(name, start, err)
+	}()
+
+	// Get the redirect policy of the mirror
+	var mirror mirrors.Mirror
+	values, err := redis.Values(conn.Do("HGETALL", fmt.Sprintf("MIRROR_%d", id)))
+	if err != nil {
+		return nil, err
+	}
+	if err = redis.ScanStruct(values, &mirror); err != nil {
+		return nil, err
+	}
+	scanner.ctx = mirrors.RedirectContext(context.Background(), &mirror)
+
 	// Try to acquire a lock so we don't have a scanning race
 	// from different nodes.
 	// Also make the key expire automatically in case our process
@@ -118,7 +135,12 @@ func Scan(typ core.ScannerType, r *database.Redis, c *mirrors.Cache, url string,
 
 	var precision core.Precision
 
+	// The metadata of the dnf repositories are checked along with the
+	// files of the versions for the metalinks
 	repoVersionList := filesystem.GetSelectorList()
+	for version, files := range filesystem.GetRepoMetadataList() {
+		repoVersionList[version] = append(repoVersionList[version], files...)
+	}
 	for _, p := range repoVersionList {
 		if len(p) == 0 {
 			continue
@@ -190,7 +212,7 @@ func Scan(typ core.ScannerType, r *database.Redis, c *mirrors.Cache, url string,
 	}
 
 	log.Infof("[%s] Indexed %d files (%d known), %d removed", name, s.count, common, len(toremove))
-	res := &ScanResult{
+	res = &ScanResult{
 		MirrorID:     id,
 		MirrorName:   name,
 		FilesIndexed: s.count,
@@ -402,6 +424,12 @@ func (s *sourcescanner) walkSource(conn redis.Conn, d *filesystem.FileData) *fil
 func ScanSource(r *database.Redis, forceRehash bool, stop <-chan struct{}) (err error) {
 	s := &sourcescanner{}
 
+	start := time.Now()
+	count := 0
+	defer func() {
+		observeSourceScan(start, count, err)
+	}()
+
 	conn := r.Get()
 	defer conn.Close()
 
@@ -455,12 +483,28 @@ func ScanSource(r *database.Redis, forceRehash bool, stop <-chan struct{}) (err
 		if length > 0 && len(path) >= length && releaseVersion == path[:length] {
 			continue
 		}
-		if filesystem.Filter(path) && len(line) >= z {
+		if filesystem.IsSignature(path) && len(line) >= z {
+			// Indexed and listed along with the file they sign
+			filesystem.AddSignature(path)
+			fd := filesystem.NewFileData(path, line[:x-1], line[x:y-1], cnf)
+			fd = s.walkSource(conn, fd)
+			if fd != nil {
+				sourceFiles = append(sourceFiles, fd)
+			}
+		} else if filesystem.Filter(path) && len(line) >= z {
 			fd := filesystem.BuildFileTree(path, line[:x-1], line[x:y-1], cnf)
 			fd = s.walkSource(conn, fd)
 			if fd != nil {
 				sourceFiles = append(sourceFiles, fd)
 			}
+		} else if filesystem.IsRepoMetadata(path) && len(line) >= z {
+			// Indexed for the dnf metalinks, out of the file tree
+			fd := filesystem.NewFileData(path, line[:x-1], line[x:y-1], cnf)
+			filesystem.AddRepoMetadata(fd)
+			fd = s.walkSource(conn, fd)
+			if fd != nil {
+				sourceFiles = append(sourceFiles, fd)
+			}
 		}
 	}
 	if err = fileScanner.Err(); err != nil {
@@ -499,7 +543,6 @@ func ScanSource(r *database.Redis, forceRehash bool, stop <-chan struct{}) (err
 	conn.Send("DEL", "FILES_TMP")
 
 	// Add all the files to a temporary key
-	count := 0
 	for _, e := range sourceFiles {
 		conn.Send("SADD", "FILES_TMP", e.Path)
 		count++
@@ -529,6 +572,7 @@ func ScanSource(r *database.Redis, forceRehash bool, stop <-chan struct{}) (err
 	if len(toremove) > 0 {
 		for _, e := range toremove {
 			conn.Send("DEL", fmt.Sprintf("FILE_%s", e))
+			conn.Send("DEL", fmt.Sprintf("TORRENT_%s", e))
 
 			// Publish update
 			database.SendPublish(conn, database.FILE_UPDATE, fmt.Sprintf("%s", e))
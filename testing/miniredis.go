@@ -0,0 +1,41 @@
+// Copyright (c) 2014-2019 Ludovic Fauvet
+// Licensed under the MIT license
+
+package testing
+
+import (
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gomodule/redigo/redis"
+	"github.com/opensourceways/mirrorbits/database"
+	"github.com/rafaeljusto/redigomock"
+)
+
+type miniredisPool struct {
+	*redis.Pool
+	ready bool
+}
+
+// Get returns a redigomock connection the first time so the database
+// is considered ready as in the other tests, then server connections
+func (p *miniredisPool) Get() redis.Conn {
+	if !p.ready {
+		p.ready = true
+		return redigomock.NewConn()
+	}
+	return p.Pool.Get()
+}
+
+// PrepareMiniredisTest initialize redis tests backed by an in-memory
+// server stopped at the end of the test
+func PrepareMiniredisTest(t miniredis.Tester) (*miniredis.Miniredis, *database.Redis) {
+	m := miniredis.RunT(t)
+	addr := m.Addr()
+	pool := &miniredisPool{
+		Pool: &redis.Pool{
+			Dial: func() (redis.Conn, error) {
+				return redis.Dial("tcp", addr)
+			},
+		},
+	}
+	return m, database.NewRedisCustomPool(pool)
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2014-2019 Ludovic Fauvet
+// Licensed under the MIT license
+
+package torrent
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+)
+
+// Encode writes the bencoded representation of v to w. The supported types
+// are strings, byte slices, integers, lists and dictionaries with string
+// keys, the keys of the dictionaries being sorted as required by BEP 3.
+func Encode(w io.Writer, v interface{}) error {
+	bw := bufio.NewWriter(w)
+	if err := encode(bw, v); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+func encode(w *bufio.Writer, v interface{}) error {
+	switch v := v.(type) {
+	case string:
+		w.WriteString(strconv.Itoa(len(v)))
+		w.WriteByte(':')
+		w.WriteString(v)
+	case []byte:
+		w.WriteString(strconv.Itoa(len(v)))
+		w.WriteByte(':')
+		w.Write(v)
+	case int:
+		return encode(w, int64(v))
+	case int64:
+		w.WriteByte('i')
+		w.WriteString(strconv.FormatInt(v, 10))
+		w.WriteByte('e')
+	case []string:
+		w.WriteByte('l')
+		for _, e := range v {
+			encode(w, e)
+		}
+		w.WriteByte('e')
+	case []interface{}:
+		w.WriteByte('l')
+		for _, e := range v {
+			if err := encode(w, e); err != nil {
+				return err
+			}
+		}
+		w.WriteByte('e')
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		w.WriteByte('d')
+		for _, k := range keys {
+			encode(w, k)
+			if err := encode(w, v[k]); err != nil {
+				return err
+			}
+		}
+		w.WriteByte('e')
+	default:
+		return fmt.Errorf("bencode: unsupported type %T", v)
+	}
+	return nil
+}
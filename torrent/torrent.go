@@ -0,0 +1,92 @@
+// Copyright (c) 2014-2019 Ludovic Fauvet
+// Licensed under the MIT license
+
+package torrent
+
+import (
+	"crypto/sha1"
+	"io"
+	"time"
+)
+
+const (
+	// minPieceLength and maxPieceLength bound the length of the pieces
+	minPieceLength = 256 << 10
+	maxPieceLength = 16 << 20
+	// targetPieces is the number of pieces aimed at for large files
+	targetPieces = 2000
+)
+
+// MetaInfo describes a single file torrent
+type MetaInfo struct {
+	Name         string
+	Length       int64
+	PieceLength  int64
+	Pieces       []byte // concatenated SHA-1 hashes of the pieces
+	WebSeeds     []string
+	Trackers     []string
+	CreatedBy    string
+	CreationDate time.Time
+}
+
+// PieceLength returns the length of the pieces of a file of the given size,
+// a power of two within [256 KiB, 16 MiB] giving about targetPieces pieces
+func PieceLength(size int64) int64 {
+	length := int64(minPieceLength)
+	for length < maxPieceLength && size/length > targetPieces {
+		length <<= 1
+	}
+	return length
+}
+
+// HashPieces returns the concatenated SHA-1 hashes of
+// the pieces of the given length read from r
+func HashPieces(r io.Reader, pieceLength int64) ([]byte, error) {
+	var pieces []byte
+	buf := make([]byte, pieceLength)
+	for {
+		n, err := io.ReadFull(r, buf)
+		if n > 0 {
+			sum := sha1.Sum(buf[:n])
+			pieces = append(pieces, sum[:]...)
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return pieces, nil
+		} else if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// Write writes the bencoded torrent file to w. The web seeds are
+// listed as BEP 19 url-list and the first tracker, if any, is used
+// as the announce URL along with a BEP 12 announce-list.
+func (m *MetaInfo) Write(w io.Writer) error {
+	info := map[string]interface{}{
+		"name":         m.Name,
+		"length":       m.Length,
+		"piece length": m.PieceLength,
+		"pieces":       m.Pieces,
+	}
+	torrent := map[string]interface{}{
+		"info": info,
+	}
+	if m.CreatedBy != "" {
+		torrent["created by"] = m.CreatedBy
+	}
+	if !m.CreationDate.IsZero() {
+		torrent["creation date"] = m.CreationDate.Unix()
+	}
+	if len(m.WebSeeds) > 0 {
+		torrent["url-list"] = m.WebSeeds
+	}
+	if len(m.Trackers) > 0 {
+		torrent["announce"] = m.Trackers[0]
+		tiers := make([]interface{}, 0, len(m.Trackers))
+		for _, t := range m.Trackers {
+			tiers = append(tiers, []string{t})
+		}
+		torrent["announce-list"] = tiers
+	}
+	return Encode(w, torrent)
+}
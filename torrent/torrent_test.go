@@ -0,0 +1,91 @@
+// Copyright (c) 2014-2019 Ludovic Fauvet
+// Licensed under the MIT license
+
+package torrent
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEncode(t *testing.T) {
+	var buf bytes.Buffer
+	err := Encode(&buf, map[string]interface{}{
+		"z": []interface{}{"a", 1},
+		"a": int64(-3),
+		"m": []byte("xy"),
+		"l": []string{"spam"},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if s := buf.String(); s != "d1:ai-3e1:ll4:spame1:m2:xy1:zl1:ai1eee" {
+		t.Fatalf("Unexpected encoding: %s", s)
+	}
+
+	if err := Encode(&buf, 1.5); err == nil {
+		t.Fatalf("Expected an error for an unsupported type")
+	}
+}
+
+func TestPieceLength(t *testing.T) {
+	for size, expected := range map[int64]int64{
+		0:         256 << 10,
+		100 << 20: 256 << 10,
+		4 << 30:   4 << 20,
+		1 << 40:   16 << 20,
+	} {
+		if l := PieceLength(size); l != expected {
+			t.Fatalf("PieceLength(%d): expected %d, got %d", size, expected, l)
+		}
+	}
+}
+
+func TestHashPieces(t *testing.T) {
+	data := []byte("0123456789")
+	pieces, err := HashPieces(bytes.NewReader(data), 4)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	var expected []byte
+	for _, p := range []string{"0123", "4567", "89"} {
+		sum := sha1.Sum([]byte(p))
+		expected = append(expected, sum[:]...)
+	}
+	if !bytes.Equal(pieces, expected) {
+		t.Fatalf("Unexpected pieces")
+	}
+}
+
+func TestMetaInfo_Write(t *testing.T) {
+	m := MetaInfo{
+		Name:         "a.iso",
+		Length:       10,
+		PieceLength:  4,
+		Pieces:       []byte("p"),
+		WebSeeds:     []string{"https://m1.example.org/a.iso"},
+		CreationDate: time.Unix(1700000000, 0),
+	}
+
+	var buf bytes.Buffer
+	if err := m.Write(&buf); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	s := buf.String()
+	if s != "d13:creation datei1700000000e4:infod6:lengthi10e4:name5:a.iso12:piece lengthi4e6:pieces1:pe8:url-listl28:https://m1.example.org/a.isoee" {
+		t.Fatalf("Unexpected torrent: %s", s)
+	}
+	if strings.Contains(s, "announce") {
+		t.Fatalf("Expected no tracker by default")
+	}
+
+	m.Trackers = []string{"udp://t1", "udp://t2"}
+	buf.Reset()
+	m.Write(&buf)
+	if !strings.Contains(buf.String(), "8:announce8:udp://t113:announce-listll8:udp://t1el8:udp://t2ee") {
+		t.Fatalf("Unexpected trackers: %s", buf.String())
+	}
+}